@@ -0,0 +1,24 @@
+#!/bin/sh
+
+# Format of Using:
+#   sh bin/compile_proto.sh
+# Must be called from the repository root. Messages shared with the inbox API
+# are imported from the goverland-inbox-api-protocol module.
+
+# exit when any command fails
+set -e
+
+INBOXAPI_MODULE=github.com/goverland-labs/goverland-inbox-api-protocol
+INBOXAPI_DIR=$(go list -m -f '{{.Dir}}' $INBOXAPI_MODULE)
+INBOXAPI_MAPPING=Minboxapi/feed.proto=$INBOXAPI_MODULE/protobuf/inboxapi
+
+# remove previously generated .pb.go files
+find protobuf -type f -name "*.pb.go" | xargs -r -L1 rm
+
+protoc --proto_path=protobuf --proto_path=$INBOXAPI_DIR/protobuf \
+  --go_out=protobuf --go-grpc_out=protobuf \
+  --go_opt=paths=source_relative,$INBOXAPI_MAPPING \
+  --go-grpc_opt=paths=source_relative,$INBOXAPI_MAPPING \
+  protobuf/feedapi/*.proto
+
+echo "Files 'protobuf/feedapi/*.proto' were compiled"
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/natsconsumer"
+)
+
+const (
+	dlqUsage             = "usage: dlq replay [limit]"
+	defaultDLQReplaySize = 100
+)
+
+func runDLQ(args []string) error {
+	if len(args) == 0 || args[0] != "replay" {
+		return fmt.Errorf("unknown command: %s", dlqUsage)
+	}
+
+	limit := defaultDLQReplaySize
+	if len(args) > 1 {
+		var err error
+		limit, err = strconv.Atoi(args[1])
+		if err != nil || limit < 1 {
+			return fmt.Errorf("invalid limit value '%s': %s", args[1], dlqUsage)
+		}
+	}
+
+	nc, err := nats.Connect(cfg.Nats.URL)
+	if err != nil {
+		return err
+	}
+	defer nc.Close()
+
+	replayed, err := natsconsumer.Replay(context.Background(), nc, cfg.Consumer.DeadLetterSubject, limit)
+	log.Info().Int("replayed", replayed).Str("subject", cfg.Consumer.DeadLetterSubject).Msg("dead letters replayed")
+
+	return err
+}
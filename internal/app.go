@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -18,9 +19,11 @@ import (
 
 	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
 	"github.com/goverland-labs/goverland-inbox-feed/internal/feed"
+	"github.com/goverland-labs/goverland-inbox-feed/internal/migrations"
 	"github.com/goverland-labs/goverland-inbox-feed/pkg/grpcsrv"
 	"github.com/goverland-labs/goverland-inbox-feed/pkg/health"
 	"github.com/goverland-labs/goverland-inbox-feed/pkg/prometheus"
+	"github.com/goverland-labs/goverland-inbox-feed/protobuf/feedapi"
 )
 
 type Application struct {
@@ -34,6 +37,7 @@ type Application struct {
 	settings      inboxapi.SettingsClient
 	feedRepo      *feed.Repo
 	feedService   *feed.Service
+	feedHub       *feed.Hub
 	coreSDK       *coresdk.Client
 }
 
@@ -105,17 +109,24 @@ func (a *Application) initDatabase() error {
 		conn = conn.Debug()
 	}
 
-	// TODO: Use real migrations instead of auto migrations from gorm
-	if err := conn.AutoMigrate(
-		&feed.Item{},
-		&feed.Settings{},
-	); err != nil {
-		return fmt.Errorf("automigrate: %w", err)
+	m, err := migrations.NewMigrator(sqlConnection)
+	if err != nil {
+		return fmt.Errorf("init migrator: %w", err)
 	}
 
-	a.feedRepo = feed.NewRepo(conn)
+	// migrations are applied by the migrate command, refuse to work with the outdated schema
+	if err = m.Check(context.Background()); err != nil {
+		return fmt.Errorf("check migrations: %w", err)
+	}
 
-	return err
+	policy, err := feed.NewUpdatePolicy(a.cfg.Feed)
+	if err != nil {
+		return fmt.Errorf("init update policy: %w", err)
+	}
+
+	a.feedRepo = feed.NewRepo(conn, policy)
+
+	return nil
 }
 
 func (a *Application) initNats() error {
@@ -159,22 +170,40 @@ func (a *Application) initCodeSDK() error {
 }
 
 func (a *Application) initServices() error {
-	a.feedService = feed.NewService(a.feedRepo, a.subscriptions, a.settings, a.coreSDK)
+	a.feedHub = feed.NewHub()
+	a.manager.AddWorker(process.NewCallbackWorker("feed-hub", a.feedHub.Start))
+
+	hubListener := feed.NewHubListener(a.natsConn, a.feedHub)
+	a.manager.AddWorker(process.NewCallbackWorker("feed-hub-listener", hubListener.Start))
+
+	backfiller := feed.NewBackfiller(a.feedRepo, a.feedRepo, a.coreSDK, a.cfg.Backfill)
+	a.manager.AddWorker(process.NewCallbackWorker("feed-backfiller", backfiller.Start))
+
+	a.feedService = feed.NewService(a.feedRepo, a.subscriptions, a.settings, backfiller)
 
 	return nil
 }
 
 func (a *Application) initFeedConsumer() error {
-	consumer := feed.NewConsumer(a.natsConn, a.feedService)
+	consumer := feed.NewConsumer(a.natsConn, a.feedService, a.publisher, a.cfg.Consumer)
 	a.manager.AddWorker(process.NewCallbackWorker("feed consumer", consumer.Start))
 
 	return nil
 }
 
 func (a *Application) initFeedWorkers() error {
-	aw := feed.NewAutoArchiveWorker(a.feedService)
+	aw := feed.NewAutoArchiveWorker(a.feedService, a.feedRepo, a.cfg.AutoArchive)
 	a.manager.AddWorker(process.NewCallbackWorker("auto-archive-worker", aw.Start))
 
+	sw := feed.NewSnoozeWorker(a.feedService)
+	a.manager.AddWorker(process.NewCallbackWorker("snooze-worker", sw.Start))
+
+	relay := feed.NewOutboxRelay(a.feedRepo, a.publisher)
+	a.manager.AddWorker(process.NewCallbackWorker("outbox-relay", relay.Start))
+
+	reconciler := feed.NewCountersReconciler(a.feedRepo)
+	a.manager.AddWorker(process.NewCallbackWorker("counters-reconciler", reconciler.Start))
+
 	return nil
 }
 
@@ -205,6 +234,7 @@ func (a *Application) registerShutdown() {
 func (a *Application) initGRPCServer() error {
 	srv := grpcsrv.NewGrpcServer()
 	inboxapi.RegisterFeedServer(srv, feed.NewServer(a.feedService))
+	feedapi.RegisterUserFeedServer(srv, feed.NewUserFeedServer(a.feedService, a.feedHub))
 
 	a.manager.AddWorker(grpcsrv.NewGrpcServerWorker("gRPC server", srv, a.cfg.Inbox.Bind))
 
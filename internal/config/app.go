@@ -1,11 +1,15 @@
 package config
 
 type App struct {
-	LogLevel   string `env:"LOG_LEVEL" envDefault:"info"`
-	Prometheus Prometheus
-	Health     Health
-	Database   Database
-	Nats       Nats
-	Inbox      Inbox
-	Core       Core
+	LogLevel    string `env:"LOG_LEVEL" envDefault:"info"`
+	Prometheus  Prometheus
+	Health      Health
+	Database    Database
+	Nats        Nats
+	Consumer    Consumer
+	Feed        Feed
+	Backfill    Backfill
+	AutoArchive AutoArchive
+	Inbox       Inbox
+	Core        Core
 }
@@ -0,0 +1,12 @@
+package config
+
+import (
+	"time"
+)
+
+type AutoArchive struct {
+	// Interval between cycles, only the instance holding the lock runs the cycle if no one has run it during the interval
+	Interval time.Duration `env:"AUTO_ARCHIVE_INTERVAL" envDefault:"1h"`
+	// BatchSize limits items checked and archived by the single transaction
+	BatchSize int `env:"AUTO_ARCHIVE_BATCH_SIZE" envDefault:"500"`
+}
@@ -0,0 +1,14 @@
+package config
+
+import (
+	"time"
+)
+
+type Backfill struct {
+	// Concurrency limits the number of backfill jobs running at the same time by the instance
+	Concurrency    int           `env:"BACKFILL_CONCURRENCY" envDefault:"4"`
+	PageSize       int           `env:"BACKFILL_PAGE_SIZE" envDefault:"100"`
+	MaxAttempts    int           `env:"BACKFILL_MAX_ATTEMPTS" envDefault:"5"`
+	InitialBackoff time.Duration `env:"BACKFILL_INITIAL_BACKOFF" envDefault:"30s"`
+	MaxBackoff     time.Duration `env:"BACKFILL_MAX_BACKOFF" envDefault:"30m"`
+}
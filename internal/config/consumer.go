@@ -0,0 +1,12 @@
+package config
+
+import (
+	"time"
+)
+
+type Consumer struct {
+	MaxDeliver        int           `env:"CONSUMER_MAX_DELIVER" envDefault:"10"`
+	InitialBackoff    time.Duration `env:"CONSUMER_INITIAL_BACKOFF" envDefault:"1s"`
+	MaxBackoff        time.Duration `env:"CONSUMER_MAX_BACKOFF" envDefault:"5m"`
+	DeadLetterSubject string        `env:"CONSUMER_DEAD_LETTER_SUBJECT" envDefault:"inbox.feed.dlq"`
+}
@@ -0,0 +1,12 @@
+package config
+
+type Feed struct {
+	// ResurfaceActions mark the read item as unread when they appear in its timeline
+	ResurfaceActions []string `env:"FEED_RESURFACE_ACTIONS" envDefault:"proposal.voting.started,proposal.voting.quorum_reached,proposal.voting.ends_soon,dao.updated"`
+	// ResurfaceStates mark the read item as unread when the proposal moves to one of them
+	ResurfaceStates []string `env:"FEED_RESURFACE_STATES" envDefault:"active,succeeded,failed,defeated"`
+	// ArchivedUpdateMode is freeze to keep archived items as is or update to update them silently
+	ArchivedUpdateMode string `env:"FEED_ARCHIVED_UPDATE_MODE" envDefault:"freeze"`
+	// UnarchiveActions unarchive the item when they appear in its timeline if the subscriber hasn't voted yet
+	UnarchiveActions []string `env:"FEED_UNARCHIVE_ACTIONS" envDefault:"proposal.voting.ends_soon"`
+}
@@ -0,0 +1,213 @@
+package feed
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// AutoArchiveAfterEnd archives proposals when Days have passed since the end of voting
+	AutoArchiveAfterEnd AutoArchiveRuleKind = "after_end"
+	// AutoArchiveReadAndEnded archives read proposals when the voting has ended
+	AutoArchiveReadAndEnded AutoArchiveRuleKind = "read_and_ended"
+	// AutoArchiveStates archives proposals in one of States at once, e.g. failed or defeated. Only final states
+	// are allowed, active and pending proposals are never archived by the rule
+	AutoArchiveStates AutoArchiveRuleKind = "states"
+	// AutoArchiveNeverDao keeps proposals of DaoIDs regardless of other rules
+	AutoArchiveNeverDao AutoArchiveRuleKind = "never_dao"
+
+	// maxAutoArchiveRules limits rules of the single subscriber, they are evaluated for every item
+	maxAutoArchiveRules = 20
+	// autoArchiveGracePeriod protects just added items, e.g. by the backfill, from archiving by time
+	autoArchiveGracePeriod = 24 * time.Hour
+	secondsInDay           = 24 * 60 * 60
+)
+
+var ErrInvalidAutoArchiveRule = errors.New("invalid auto-archive rule")
+
+type AutoArchiveRuleKind string
+
+// AutoArchiveRule is the condition the auto-archive worker checks for every not archived proposal of the subscriber.
+type AutoArchiveRule struct {
+	Kind   AutoArchiveRuleKind `json:"kind"`
+	Days   int                 `json:"days,omitempty"`
+	States []string            `json:"states,omitempty"`
+	DaoIDs []uuid.UUID         `json:"dao_ids,omitempty"`
+}
+
+// AutoArchiveRules archives the proposal if any rule matches it and none of AutoArchiveNeverDao rules excludes it.
+type AutoArchiveRules []AutoArchiveRule
+
+// defaultAutoArchiveRules is used when the subscriber hasn't set rules, it keeps the behaviour of autoarchive_after_days.
+func defaultAutoArchiveRules(afterDays int) AutoArchiveRules {
+	return AutoArchiveRules{{Kind: AutoArchiveAfterEnd, Days: afterDays}}
+}
+
+func (rules AutoArchiveRules) Validate() error {
+	if len(rules) > maxAutoArchiveRules {
+		return fmt.Errorf("%w: more than %d rules", ErrInvalidAutoArchiveRule, maxAutoArchiveRules)
+	}
+
+	for idx, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("%w: rule #%d: %s", ErrInvalidAutoArchiveRule, idx, err)
+		}
+	}
+
+	return nil
+}
+
+func (r AutoArchiveRule) validate() error {
+	switch r.Kind {
+	case AutoArchiveAfterEnd:
+		if r.Days < 0 {
+			return errors.New("negative days")
+		}
+	case AutoArchiveReadAndEnded:
+	case AutoArchiveStates:
+		if len(r.States) == 0 {
+			return errors.New("no states")
+		}
+
+		for _, state := range r.States {
+			if !slices.Contains(actualityProposalStates, state) {
+				return fmt.Errorf("unknown state %q", state)
+			}
+
+			if slices.Contains(activeProposalStates, state) {
+				return fmt.Errorf("state %q isn't final", state)
+			}
+		}
+	case AutoArchiveNeverDao:
+		if len(r.DaoIDs) == 0 {
+			return errors.New("no daos")
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", r.Kind)
+	}
+
+	return nil
+}
+
+// autoArchiveSnapshot is the part of the proposal snapshot used by rules.
+type autoArchiveSnapshot struct {
+	State string  `json:"state"`
+	End   float64 `json:"end"`
+}
+
+// Match checks if the item has to be archived at the time. Only proposals are archived, others don't have the end.
+func (rules AutoArchiveRules) Match(item *Item, now time.Time) bool {
+	if item.Type != Proposal || item.ArchivedAt != nil {
+		return false
+	}
+
+	for _, rule := range rules {
+		if rule.Kind == AutoArchiveNeverDao && slices.Contains(rule.DaoIDs, item.DaoID) {
+			return false
+		}
+	}
+
+	var snapshot autoArchiveSnapshot
+	if err := json.Unmarshal(item.Snapshot, &snapshot); err != nil {
+		return false
+	}
+
+	for _, rule := range rules {
+		if rule.match(item, snapshot, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r AutoArchiveRule) match(item *Item, snapshot autoArchiveSnapshot, now time.Time) bool {
+	ended := snapshot.End > 0 && int64(snapshot.End) <= now.Unix()
+
+	switch r.Kind {
+	case AutoArchiveAfterEnd:
+		// days are counted by dates, so the item ended yesterday is expired for 1 day
+		expiredDays := now.Unix()/secondsInDay - int64(snapshot.End)/secondsInDay
+
+		return ended && expiredDays > 0 && expiredDays > int64(r.Days) &&
+			item.CreatedAt.Before(now.Add(-autoArchiveGracePeriod))
+	case AutoArchiveReadAndEnded:
+		return ended && item.ReadAt != nil
+	case AutoArchiveStates:
+		// rules stored before the validation of final states don't archive active proposals
+		return slices.Contains(r.States, snapshot.State) && !slices.Contains(activeProposalStates, snapshot.State)
+	default:
+		return false
+	}
+}
+
+// autoArchiveEndExpr is the end of the proposal, the end which isn't a number is treated as unknown like Match does.
+const autoArchiveEndExpr = `case when jsonb_typeof(snapshot->'end') = 'number' then (snapshot->>'end')::numeric end`
+
+// FilterAutoArchiveCandidates keeps proposals which could be matched by auto-archive rules at the time:
+// ones in the final state or with the voting ended. Rules are checked for the rest of them.
+func FilterAutoArchiveCandidates(now time.Time) Filter {
+	var (
+		dummy Item
+		_     = dummy.Snapshot // state and end
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			// the end is truncated to seconds by rules, so it's ended before the next second
+			return query.Where(`(snapshot->>'state' not in @active or (`+autoArchiveEndExpr+`) > 0 and (`+autoArchiveEndExpr+`) < @next)`,
+				sql.Named("active", activeProposalStates),
+				sql.Named("next", now.Unix()+1),
+			)
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				if state, ok := snapshotText(item, "state"); ok && !slices.Contains(activeProposalStates, state) {
+					return true
+				}
+
+				var snapshot struct {
+					End any `json:"end"`
+				}
+				if err := json.Unmarshal(item.Snapshot, &snapshot); err != nil {
+					return false
+				}
+
+				end, ok := snapshot.End.(float64)
+
+				return ok && end > 0 && end < float64(now.Unix()+1)
+			})
+		},
+	}
+}
+
+// WithAutoArchiveFields loads only fields used by auto-archive rules, the snapshot keeps the state and the end.
+// The memory store returns whole items.
+func WithAutoArchiveFields() Filter {
+	var (
+		dummy Item
+		_     = dummy.ID
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+		_     = dummy.Type
+		_     = dummy.CreatedAt
+		_     = dummy.ReadAt
+		_     = dummy.ArchivedAt
+		_     = dummy.Snapshot
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Select("id, subscriber_id, dao_id, type, created_at, read_at, archived_at, " +
+				"jsonb_build_object('state', snapshot->'state', 'end', snapshot->'end') as snapshot")
+		},
+		memory: func(*memoryQuery) {},
+	}
+}
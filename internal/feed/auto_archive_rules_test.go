@@ -0,0 +1,207 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+)
+
+func TestAutoArchiveRules_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rules AutoArchiveRules
+		valid bool
+	}{
+		"empty":          {valid: true},
+		"after end":      {rules: AutoArchiveRules{{Kind: AutoArchiveAfterEnd, Days: 3}}, valid: true},
+		"read and ended": {rules: AutoArchiveRules{{Kind: AutoArchiveReadAndEnded}}, valid: true},
+		"states":         {rules: AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{ProposalStateFailed}}}, valid: true},
+		"never dao":      {rules: AutoArchiveRules{{Kind: AutoArchiveNeverDao, DaoIDs: []uuid.UUID{uuid.New()}}}, valid: true},
+		"negative days":  {rules: AutoArchiveRules{{Kind: AutoArchiveAfterEnd, Days: -1}}},
+		"no states":      {rules: AutoArchiveRules{{Kind: AutoArchiveStates}}},
+		"unknown state":  {rules: AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{"closed"}}}},
+		"active state":   {rules: AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{ProposalStateFailed, ProposalStateActive}}}},
+		"pending state":  {rules: AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{ProposalStatePending}}}},
+		"no daos":        {rules: AutoArchiveRules{{Kind: AutoArchiveNeverDao}}},
+		"unknown kind":   {rules: AutoArchiveRules{{Kind: "always"}}},
+		"too many rules": {rules: make(AutoArchiveRules, maxAutoArchiveRules+1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.rules.Validate()
+			assert.Equal(t, tc.valid, err == nil)
+			if !tc.valid {
+				assert.ErrorIs(t, err, ErrInvalidAutoArchiveRule)
+			}
+		})
+	}
+}
+
+func TestAutoArchiveRules_Match(t *testing.T) {
+	day := 24 * time.Hour
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	dao := uuid.New()
+
+	proposal := func(state string, endedAgo time.Duration, mods ...func(item *Item)) Item {
+		item := Item{
+			DaoID:     dao,
+			Type:      Proposal,
+			CreatedAt: now.Add(-30 * day),
+			Snapshot:  []byte(fmt.Sprintf(`{"state":%q,"end":%d}`, state, now.Add(-endedAgo).Unix())),
+		}
+		for _, mod := range mods {
+			mod(&item)
+		}
+
+		return item
+	}
+	read := func(item *Item) { item.ReadAt = &now }
+
+	afterWeek := AutoArchiveRules{{Kind: AutoArchiveAfterEnd, Days: 7}}
+
+	for name, tc := range map[string]struct {
+		rules    AutoArchiveRules
+		item     Item
+		expected bool
+	}{
+		"expired": {
+			rules:    afterWeek,
+			item:     proposal(ProposalStateSucceeded, 10*day),
+			expected: true,
+		},
+		"not expired yet": {
+			rules: afterWeek,
+			item:  proposal(ProposalStateSucceeded, 7*day),
+		},
+		"grace period": {
+			rules: afterWeek,
+			item: proposal(ProposalStateSucceeded, 10*day, func(item *Item) {
+				item.CreatedAt = now.Add(-time.Hour)
+			}),
+		},
+		"not ended": {
+			rules: AutoArchiveRules{{Kind: AutoArchiveAfterEnd}},
+			item:  proposal(ProposalStateActive, -day),
+		},
+		"read and ended": {
+			rules:    AutoArchiveRules{{Kind: AutoArchiveReadAndEnded}},
+			item:     proposal(ProposalStateSucceeded, time.Hour, read),
+			expected: true,
+		},
+		"read and not ended": {
+			rules: AutoArchiveRules{{Kind: AutoArchiveReadAndEnded}},
+			item:  proposal(ProposalStateActive, -time.Hour, read),
+		},
+		"unread and ended": {
+			rules: AutoArchiveRules{{Kind: AutoArchiveReadAndEnded}},
+			item:  proposal(ProposalStateSucceeded, time.Hour),
+		},
+		"state": {
+			rules:    AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{ProposalStateFailed, ProposalStateDefeated}}},
+			item:     proposal(ProposalStateDefeated, 0),
+			expected: true,
+		},
+		"other state": {
+			rules: AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{ProposalStateFailed, ProposalStateDefeated}}},
+			item:  proposal(ProposalStateSucceeded, 10*day),
+		},
+		"active state": {
+			rules: AutoArchiveRules{{Kind: AutoArchiveStates, States: []string{ProposalStateActive}}},
+			item:  proposal(ProposalStateActive, -time.Hour),
+		},
+		"any rule": {
+			rules:    append(AutoArchiveRules{{Kind: AutoArchiveReadAndEnded}}, afterWeek...),
+			item:     proposal(ProposalStateSucceeded, 10*day),
+			expected: true,
+		},
+		"never dao": {
+			rules: append(AutoArchiveRules{{Kind: AutoArchiveNeverDao, DaoIDs: []uuid.UUID{dao}}}, afterWeek...),
+			item:  proposal(ProposalStateSucceeded, 10*day),
+		},
+		"never other dao": {
+			rules:    append(AutoArchiveRules{{Kind: AutoArchiveNeverDao, DaoIDs: []uuid.UUID{uuid.New()}}}, afterWeek...),
+			item:     proposal(ProposalStateSucceeded, 10*day),
+			expected: true,
+		},
+		"archived": {
+			rules: afterWeek,
+			item: proposal(ProposalStateSucceeded, 10*day, func(item *Item) {
+				item.ArchivedAt = &now
+			}),
+		},
+		"discussion": {
+			rules: AutoArchiveRules{{Kind: AutoArchiveAfterEnd}},
+			item: proposal(ProposalStateSucceeded, 10*day, func(item *Item) {
+				item.Type = Discussion
+			}),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rules.Match(&tc.item, now))
+		})
+	}
+}
+
+// settingsLookupStore records the largest number of subscribers requested by FindSettings.
+type settingsLookupStore struct {
+	*MemoryStore
+	largest int
+}
+
+func (s *settingsLookupStore) FindSettings(ctx context.Context, subscriberIDs []uuid.UUID) ([]Settings, error) {
+	s.largest = max(s.largest, len(subscriberIDs))
+
+	return s.MemoryStore.FindSettings(ctx, subscriberIDs)
+}
+
+func TestService_AutoArchive(t *testing.T) {
+	ctx := context.Background()
+	store := &settingsLookupStore{MemoryStore: NewMemoryStore(UpdatePolicy{})}
+	service := NewService(store, nil, nil, nil)
+	subscriber, custom, ruled := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: custom, AutoarchiveAfterDays: 2}))
+	require.NoError(t, store.SetAutoArchiveRules(ctx, ruled, AutoArchiveRules{
+		{Kind: AutoArchiveStates, States: []string{ProposalStateDefeated}},
+	}))
+
+	day := 24 * time.Hour
+	ended := func(subscriberID uuid.UUID, proposalID, state string, createdAgo, endedAgo time.Duration) Item {
+		item := storeTestItem(subscriberID, proposalID, state, 1)
+		item.CreatedAt = time.Now().Add(-createdAgo)
+		item.Snapshot = []byte(fmt.Sprintf(`{"state":%q,"created":1,"end":%d}`, state, time.Now().Add(-endedAgo).Unix()))
+
+		return item
+	}
+
+	require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{
+		ended(subscriber, "expired", ProposalStateSucceeded, 30*day, 10*day),
+		ended(subscriber, "recent", ProposalStateSucceeded, 30*day, 3*day),
+		ended(subscriber, "just-created", ProposalStateSucceeded, time.Hour, 10*day),
+		ended(custom, "expired", ProposalStateSucceeded, 30*day, 10*day),
+		ended(custom, "recent", ProposalStateSucceeded, 30*day, 4*day),
+		ended(ruled, "expired", ProposalStateSucceeded, 30*day, 10*day),
+		ended(ruled, "defeated", ProposalStateDefeated, time.Hour, time.Hour),
+	}))
+
+	// the small batch checks that all items are walked through
+	archived, err := service.markExpiredAsAutoArchived(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, archived)
+	assert.LessOrEqual(t, store.largest, 2, "settings are loaded by batches")
+
+	for subscriberID, expected := range map[uuid.UUID][]string{
+		subscriber: {"expired"},
+		custom:     {"expired", "recent"},
+		ruled:      {"defeated"},
+	} {
+		list, err := store.FindByFilters(ctx, []Filter{FilterByArchivedStatus(helpers.Ptr(true)), FilterBySubscriberID(subscriberID)})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, expected, proposalIDs(list))
+		assertCounters(t, store, subscriberID)
+	}
+}
@@ -4,37 +4,149 @@ import (
 	"context"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog/log"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
 )
 
-const (
-	autoArchiveCheckDelay = time.Hour
+// autoArchiveLockKey is the advisory lock held by the instance running the auto-archive cycle,
+// it must be unique across advisory locks of the database.
+const autoArchiveLockKey int64 = 0x6175746f61726368 // "autoarch"
+
+// autoArchiveJob is the name of the auto-archive job in job runs.
+const autoArchiveJob = "auto_archive"
+
+var (
+	autoArchivedItems = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "inbox_feed",
+		Subsystem: "auto_archive",
+		Name:      "archived_items_total",
+		Help:      "The number of items archived by auto-archive rules.",
+	})
+	autoArchiveDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "inbox_feed",
+		Subsystem: "auto_archive",
+		Name:      "cycle_duration_seconds",
+		Help:      "The duration of auto-archive cycles run by the instance holding the lock.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	})
+	autoArchiveFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "inbox_feed",
+		Subsystem: "auto_archive",
+		Name:      "failures_total",
+		Help:      "The number of failed auto-archive cycles.",
+	})
 )
 
+// Locker elects the single instance to run the periodic job, the release func has to be called when the job is done.
+type Locker interface {
+	TryLock(ctx context.Context, key int64) (release func() error, acquired bool, err error)
+}
+
+// JobRun is the last run of the periodic job shared by all instances.
+type JobRun struct {
+	Name      string `gorm:"primaryKey"`
+	StartedAt time.Time
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// JobRunStore stores last runs of periodic jobs, so the job is run once per interval whatever the number of instances.
+type JobRunStore interface {
+	// LastJobRun returns the start of the last run of the job, zero time if it has never been run.
+	LastJobRun(ctx context.Context, name string) (time.Time, error)
+	SaveJobRun(ctx context.Context, name string, startedAt time.Time) error
+}
+
+// Scheduler runs the periodic job on the single instance once per interval.
+type Scheduler interface {
+	Locker
+	JobRunStore
+}
+
 type AutoArchiveWorker struct {
-	service *Service
+	service   *Service
+	scheduler Scheduler
+	cfg       config.AutoArchive
 }
 
-func NewAutoArchiveWorker(s *Service) *AutoArchiveWorker {
+func NewAutoArchiveWorker(s *Service, scheduler Scheduler, cfg config.AutoArchive) *AutoArchiveWorker {
 	return &AutoArchiveWorker{
-		service: s,
+		service:   s,
+		scheduler: scheduler,
+		cfg:       cfg,
 	}
 }
 
 func (w *AutoArchiveWorker) Start(ctx context.Context) error {
 	for {
-		start := time.Now()
-		err := w.service.markExpiredAsAutoArchived(ctx)
-		if err != nil {
-			log.Error().Err(err).Msg("auto archive feed items")
-		}
-
-		log.Debug().Msgf("auto archive feed items completed: %v", time.Since(start))
+		w.cycle(ctx)
 
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-time.After(autoArchiveCheckDelay):
+		case <-time.After(w.cfg.Interval):
+		}
+	}
+}
+
+// cycle archives items if no other instance is doing it at the moment or has done it during the last interval
+// and returns if the cycle was run. Every instance wakes up once per interval, so without the last run
+// the cycle would be repeated by every instance.
+func (w *AutoArchiveWorker) cycle(ctx context.Context) bool {
+	release, acquired, err := w.scheduler.TryLock(ctx, autoArchiveLockKey)
+	if err != nil {
+		autoArchiveFailures.Inc()
+		log.Error().Err(err).Msg("take auto archive lock")
+
+		return false
+	}
+
+	if !acquired {
+		log.Debug().Msg("auto archive feed items is run by another instance")
+
+		return false
+	}
+
+	defer func() {
+		if err := release(); err != nil {
+			log.Error().Err(err).Msg("release auto archive lock")
 		}
+	}()
+
+	last, err := w.scheduler.LastJobRun(ctx, autoArchiveJob)
+	if err != nil {
+		autoArchiveFailures.Inc()
+		log.Error().Err(err).Msg("get the last auto archive run")
+
+		return false
 	}
+
+	if time.Since(last) < w.cfg.Interval {
+		log.Debug().Time("last_run", last).Msg("auto archive feed items was run recently")
+
+		return false
+	}
+
+	start := time.Now()
+	archived, err := w.service.markExpiredAsAutoArchived(ctx, w.cfg.BatchSize)
+	autoArchivedItems.Add(float64(archived))
+	autoArchiveDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		autoArchiveFailures.Inc()
+		log.Error().Err(err).Int("archived", archived).Msg("auto archive feed items")
+	}
+
+	log.Debug().Int("archived", archived).Msgf("auto archive feed items completed: %v", time.Since(start))
+
+	// failed cycles are stored as well, they are retried by the next interval like before
+	if err = w.scheduler.SaveJobRun(ctx, autoArchiveJob, start); err != nil {
+		log.Error().Err(err).Msg("save the auto archive run")
+	}
+
+	return true
 }
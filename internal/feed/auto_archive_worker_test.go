@@ -0,0 +1,53 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+)
+
+func TestAutoArchiveWorker_Cycle(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	worker := NewAutoArchiveWorker(NewService(store, nil, nil, nil), store, config.AutoArchive{
+		Interval:  time.Hour,
+		BatchSize: 10,
+	})
+	subscriber := uuid.New()
+
+	expired := storeTestItem(subscriber, "expired", ProposalStateSucceeded, 1)
+	expired.CreatedAt = time.Now().Add(-30 * 24 * time.Hour)
+	expired.Snapshot = []byte(fmt.Sprintf(`{"state":"succeeded","created":1,"end":%d}`, expired.CreatedAt.Unix()))
+	require.NoError(t, store.CreateOrUpdate(ctx, &expired))
+
+	// another instance runs the cycle
+	release, acquired, err := store.TryLock(ctx, autoArchiveLockKey)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	assert.False(t, worker.cycle(ctx))
+	assertCount(t, store, 0, FilterByArchivedStatus(helpers.Ptr(true)))
+
+	require.NoError(t, release())
+	assert.True(t, worker.cycle(ctx))
+	assertCount(t, store, 1, FilterByArchivedStatus(helpers.Ptr(true)))
+
+	release, acquired, err = store.TryLock(ctx, autoArchiveLockKey)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the lock is released after the cycle")
+	require.NoError(t, release())
+
+	// another instance wakes up during the same interval
+	assert.False(t, worker.cycle(ctx))
+
+	require.NoError(t, store.SaveJobRun(ctx, autoArchiveJob, time.Now().Add(-time.Hour)))
+	assert.True(t, worker.cycle(ctx))
+}
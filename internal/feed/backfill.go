@@ -0,0 +1,388 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	coresdk "github.com/goverland-labs/goverland-core-sdk-go"
+	"github.com/goverland-labs/goverland-core-sdk-go/feed"
+	"github.com/rs/zerolog/log"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+)
+
+const (
+	backfillPollDelay = 5 * time.Second
+	// backfillStallTimeout is the time after which the running job without progress is claimed again,
+	// e.g. when the instance which ran it was killed
+	backfillStallTimeout = 10 * time.Minute
+	// backfillOverlapRatio defines the part of the page which is requested again by the next page
+	backfillOverlapRatio = 10
+)
+
+// backfillTypes are types of core feed items which are added to the feed of the new subscriber
+var backfillTypes = []string{string(Proposal), string(Discussion)}
+
+type BackfillStatus string
+
+const (
+	BackfillPending   BackfillStatus = "pending"
+	BackfillRunning   BackfillStatus = "running"
+	BackfillCompleted BackfillStatus = "completed"
+	// BackfillFailed means the job ran out of attempts
+	BackfillFailed BackfillStatus = "failed"
+	// BackfillCanceled means the subscriber left the dao before the job finished
+	BackfillCanceled BackfillStatus = "canceled"
+)
+
+// ErrBackfillNotRunning means the claimed job was changed by someone else, e.g. it's canceled by the unsubscribe
+// or claimed again as stalled.
+var ErrBackfillNotRunning = errors.New("backfill job isn't running")
+
+// BackfillJob fills the subscriber feed with active proposals and discussions of the dao the subscriber joined.
+// There is the single job per subscriber and dao.
+type BackfillJob struct {
+	SubscriberID uuid.UUID `gorm:"primary_key"`
+	DaoID        uuid.UUID `gorm:"primary_key"`
+	Status       BackfillStatus
+	// Total is the number of items in the core feed reported by the last page
+	Total int
+	// Processed is the number of stored items, the job continues from it after the failure
+	Processed int
+	Attempts  int
+	// Error is the last error of the job
+	Error string
+	// Claim is increased by every claim and replace of the job, only the worker of the last claim saves it
+	Claim      int64
+	NextRunAt  time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+func (BackfillJob) TableName() string {
+	return "backfill_jobs"
+}
+
+// BackfillStore stores backfill jobs.
+type BackfillStore interface {
+	// EnqueueBackfill schedules the job from the beginning. Pending and running jobs are kept as is,
+	// so repeated calls don't restart them.
+	EnqueueBackfill(ctx context.Context, subscriberID, daoID uuid.UUID) (BackfillJob, error)
+	// ClaimBackfills marks up to limit jobs which are ready to run or were updated by the running instance
+	// before stalledBefore as running and returns them.
+	ClaimBackfills(ctx context.Context, limit int, stalledBefore time.Time) ([]BackfillJob, error)
+	// SaveBackfill stores the progress and the status of the claimed job.
+	// Returns ErrBackfillNotRunning if the job isn't running anymore or it has the newer claim.
+	SaveBackfill(ctx context.Context, job *BackfillJob) error
+	// ReplaceBackfill stores the job as is, e.g. the one finished without the claim.
+	ReplaceBackfill(ctx context.Context, job *BackfillJob) error
+	// FindBackfills returns jobs of the subscriber sorted by dao, uuid.Nil dao id means all daos.
+	FindBackfills(ctx context.Context, subscriberID, daoID uuid.UUID) ([]BackfillJob, error)
+}
+
+// CoreFeed is the feed of core, coresdk.Client implements it.
+type CoreFeed interface {
+	GetFeedByFilters(ctx context.Context, params coresdk.FeedByFiltersRequest) (*feed.Feed, error)
+}
+
+// Backfiller runs backfill jobs in background with bounded concurrency. Jobs go through the core feed
+// page by page and store the progress after every page, so retries continue from the last stored page.
+// Items are upserted, so processing the same page twice is harmless.
+type Backfiller struct {
+	store  BackfillStore
+	items  FeedStore
+	core   CoreFeed
+	cfg    config.Backfill
+	wakeup chan struct{}
+}
+
+func NewBackfiller(store BackfillStore, items FeedStore, core CoreFeed, cfg config.Backfill) *Backfiller {
+	return &Backfiller{
+		store:  store,
+		items:  items,
+		core:   core,
+		cfg:    cfg,
+		wakeup: make(chan struct{}, 1),
+	}
+}
+
+// Enqueue schedules the backfill and wakes up the worker.
+func (b *Backfiller) Enqueue(ctx context.Context, subscriberID, daoID uuid.UUID) (BackfillJob, error) {
+	job, err := b.store.EnqueueBackfill(ctx, subscriberID, daoID)
+	if err != nil {
+		return job, err
+	}
+
+	b.notify()
+
+	return job, nil
+}
+
+// Find returns backfill jobs of the subscriber, uuid.Nil dao id means all daos.
+func (b *Backfiller) Find(ctx context.Context, subscriberID, daoID uuid.UUID) ([]BackfillJob, error) {
+	return b.store.FindBackfills(ctx, subscriberID, daoID)
+}
+
+func (b *Backfiller) Start(ctx context.Context) error {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, b.cfg.Concurrency)
+
+	for {
+		b.runReady(ctx, slots, &wg)
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+
+			return nil
+		case <-b.wakeup:
+		case <-time.After(backfillPollDelay):
+		}
+	}
+}
+
+func (b *Backfiller) notify() {
+	select {
+	case b.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// runReady claims jobs for free slots and runs them in background.
+func (b *Backfiller) runReady(ctx context.Context, slots chan struct{}, wg *sync.WaitGroup) {
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return
+	}
+
+	jobs, err := b.store.ClaimBackfills(ctx, free, time.Now().Add(-backfillStallTimeout))
+	if err != nil {
+		log.Error().Err(err).Msg("claim backfill jobs")
+		return
+	}
+
+	for _, job := range jobs {
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+				b.notify()
+			}()
+
+			b.run(ctx, &job)
+		}()
+	}
+}
+
+// run processes the claimed job and stores its outcome.
+func (b *Backfiller) run(ctx context.Context, job *BackfillJob) {
+	err := b.process(ctx, job)
+
+	now := time.Now()
+	switch {
+	case errors.Is(err, ErrBackfillNotRunning):
+		log.Info().
+			Str("subscriber_id", job.SubscriberID.String()).
+			Str("dao_id", job.DaoID.String()).
+			Msg("backfill is stopped")
+
+		return
+	case err == nil:
+		job.Status = BackfillCompleted
+		job.Error = ""
+		job.FinishedAt = &now
+	case ctx.Err() != nil:
+		// the instance is stopping, the job is continued by the next claim
+		job.Status = BackfillPending
+		job.NextRunAt = now
+		ctx = context.WithoutCancel(ctx)
+	default:
+		job.Attempts++
+		job.Error = err.Error()
+		job.Status = BackfillPending
+		job.NextRunAt = now.Add(b.backoff(job.Attempts))
+		if job.Attempts >= b.cfg.MaxAttempts {
+			job.Status = BackfillFailed
+			job.FinishedAt = &now
+		}
+
+		log.Warn().
+			Err(err).
+			Str("subscriber_id", job.SubscriberID.String()).
+			Str("dao_id", job.DaoID.String()).
+			Int("attempts", job.Attempts).
+			Msg("backfill failed")
+	}
+
+	if err = b.store.SaveBackfill(ctx, job); err != nil && !errors.Is(err, ErrBackfillNotRunning) {
+		log.Error().Err(err).Str("subscriber_id", job.SubscriberID.String()).Msg("save backfill job")
+	}
+}
+
+// process stores active proposals of the dao page by page starting from the processed ones.
+// The core feed is paginated by offsets only, so every page starts a bit earlier than the previous one ended:
+// proposals which end in the meantime shift the rest back and they would be skipped otherwise.
+func (b *Backfiller) process(ctx context.Context, job *BackfillJob) error {
+	for {
+		offset := job.Processed - min(job.Processed, b.cfg.PageSize/backfillOverlapRatio)
+		page, err := b.core.GetFeedByFilters(ctx, coresdk.FeedByFiltersRequest{
+			IsActive: helpers.Ptr(true),
+			DaoList:  []string{job.DaoID.String()},
+			Types:    backfillTypes,
+			Offset:   offset,
+			Limit:    b.cfg.PageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("get feed by filters: %w", err)
+		}
+
+		items := make([]Item, 0, len(page.Items))
+		for _, item := range page.Items {
+			items = append(items, *convertCoreFeedItemToInternal(job.SubscriberID, item))
+		}
+
+		if err = b.items.BulkCreateOrUpdate(ctx, items); err != nil {
+			return fmt.Errorf("store items: %w", err)
+		}
+
+		job.Processed = offset + len(items)
+		job.Total = max(page.TotalCnt, job.Processed)
+
+		if len(items) < b.cfg.PageSize || job.Processed >= page.TotalCnt {
+			return nil
+		}
+
+		// the progress is stored after every page, it keeps the job from being claimed as stalled as well
+		if err = b.store.SaveBackfill(ctx, job); err != nil {
+			return fmt.Errorf("save progress: %w", err)
+		}
+	}
+}
+
+// BackfillMany fills the subscriber feed with active proposals of many daos at once: proposals of all daos are loaded
+// from core by the same requests, deduplicated and stored by the single batch. Jobs of daos are stored as completed
+// with the number of their items. If it fails, jobs are enqueued and retried in background one by one.
+func (b *Backfiller) BackfillMany(ctx context.Context, subscriberID uuid.UUID, daoIDs []uuid.UUID) ([]BackfillJob, error) {
+	daoIDs = slices.Clone(daoIDs)
+	slices.SortFunc(daoIDs, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	daoIDs = slices.Compact(daoIDs)
+
+	counts, err := b.storeMany(ctx, subscriberID, daoIDs)
+	if err != nil {
+		log.Warn().Err(err).Str("subscriber_id", subscriberID.String()).Msg("backfill many daos, fallback to background jobs")
+
+		return b.enqueueMany(ctx, subscriberID, daoIDs)
+	}
+
+	now := time.Now()
+	jobs := make([]BackfillJob, 0, len(daoIDs))
+	for _, daoID := range daoIDs {
+		job := BackfillJob{
+			SubscriberID: subscriberID,
+			DaoID:        daoID,
+			Status:       BackfillCompleted,
+			Total:        counts[daoID],
+			Processed:    counts[daoID],
+			NextRunAt:    now,
+			CreatedAt:    now,
+			FinishedAt:   &now,
+		}
+		if err = b.store.ReplaceBackfill(ctx, &job); err != nil {
+			return nil, fmt.Errorf("save backfill: %w", err)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// storeMany stores active proposals of daos by the single batch and returns the number of stored items by daos.
+func (b *Backfiller) storeMany(ctx context.Context, subscriberID uuid.UUID, daoIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	daoList := make([]string, 0, len(daoIDs))
+	for _, daoID := range daoIDs {
+		daoList = append(daoList, daoID.String())
+	}
+
+	var (
+		items []Item
+		seen  = make(map[uuid.UUID]struct{})
+	)
+	// pages overlap like in process, duplicates are skipped by their ids
+	step := b.cfg.PageSize - b.cfg.PageSize/backfillOverlapRatio
+	for offset := 0; ; offset += step {
+		page, err := b.core.GetFeedByFilters(ctx, coresdk.FeedByFiltersRequest{
+			IsActive: helpers.Ptr(true),
+			DaoList:  daoList,
+			Types:    backfillTypes,
+			Offset:   offset,
+			Limit:    b.cfg.PageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get feed by filters: %w", err)
+		}
+
+		for _, item := range page.Items {
+			if _, ok := seen[item.ID]; ok {
+				continue
+			}
+
+			seen[item.ID] = struct{}{}
+			items = append(items, *convertCoreFeedItemToInternal(subscriberID, item))
+		}
+
+		if len(page.Items) < b.cfg.PageSize || offset+len(page.Items) >= page.TotalCnt {
+			break
+		}
+	}
+
+	if err := b.items.BulkCreateOrUpdate(ctx, items); err != nil {
+		return nil, fmt.Errorf("store items: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int, len(daoIDs))
+	for _, item := range items {
+		counts[item.DaoID]++
+	}
+
+	return counts, nil
+}
+
+func (b *Backfiller) enqueueMany(ctx context.Context, subscriberID uuid.UUID, daoIDs []uuid.UUID) ([]BackfillJob, error) {
+	jobs := make([]BackfillJob, 0, len(daoIDs))
+	for _, daoID := range daoIDs {
+		job, err := b.store.EnqueueBackfill(ctx, subscriberID, daoID)
+		if err != nil {
+			return nil, fmt.Errorf("enqueue backfill: %w", err)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	b.notify()
+
+	return jobs, nil
+}
+
+// backoff returns the delay before the next attempt, it's doubled after every failed attempt.
+func (b *Backfiller) backoff(attempts int) time.Duration {
+	delay := b.cfg.InitialBackoff
+	for i := 1; i < attempts && delay < b.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.cfg.MaxBackoff)
+}
@@ -0,0 +1,234 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	coresdk "github.com/goverland-labs/goverland-core-sdk-go"
+	"github.com/goverland-labs/goverland-core-sdk-go/feed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
+)
+
+// fakeCoreFeed returns items by pages and fails the request with the given offset once.
+type fakeCoreFeed struct {
+	items     []feed.Item
+	failAt    int
+	failed    bool
+	requests  []int
+	onRequest func(offset int)
+}
+
+func (f *fakeCoreFeed) GetFeedByFilters(_ context.Context, params coresdk.FeedByFiltersRequest) (*feed.Feed, error) {
+	f.requests = append(f.requests, params.Offset)
+	if f.onRequest != nil {
+		f.onRequest(params.Offset)
+	}
+	if params.Offset == f.failAt && !f.failed {
+		f.failed = true
+		return nil, errors.New("core is unavailable")
+	}
+
+	end := min(params.Offset+params.Limit, len(f.items))
+
+	return &feed.Feed{
+		Items:    f.items[params.Offset:end],
+		TotalCnt: len(f.items),
+		Offset:   params.Offset,
+		Limit:    params.Limit,
+	}, nil
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	ctx := context.Background()
+	subscriber, dao := uuid.New(), uuid.New()
+
+	items := make([]feed.Item, 0, 25)
+	for i := range 25 {
+		items = append(items, feed.Item{
+			ID:         uuid.New(),
+			DaoID:      dao,
+			ProposalID: uuid.NewString(),
+			Type:       "proposal",
+			Action:     "proposal.created",
+			Snapshot:   storeTestSnapshot(ProposalStateActive, int64(i)),
+			Timeline:   []byte(`[]`),
+		})
+	}
+
+	for name, tc := range map[string]struct {
+		failAt int
+		// unsubscribeAt is the offset of the request during which the subscriber leaves the dao, 0 means never
+		unsubscribeAt int
+		// endAt is the offset of the request before which the first proposal leaves the core feed, 0 means never
+		endAt    int
+		status   BackfillStatus
+		requests []int
+	}{
+		"all pages": {
+			failAt:   -1,
+			status:   BackfillCompleted,
+			requests: []int{0, 9, 18},
+		},
+		"retry from the failed page": {
+			failAt:   9,
+			status:   BackfillCompleted,
+			requests: []int{0, 9, 9, 18},
+		},
+		"proposal ends between pages": {
+			failAt:   -1,
+			endAt:    9,
+			status:   BackfillCompleted,
+			requests: []int{0, 9, 18},
+		},
+		"out of attempts": {
+			failAt:   0,
+			status:   BackfillFailed,
+			requests: []int{0},
+		},
+		"canceled by unsubscribe": {
+			failAt:        -1,
+			unsubscribeAt: 9,
+			status:        BackfillCanceled,
+			requests:      []int{0, 9},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := NewMemoryStore(UpdatePolicy{})
+			core := &fakeCoreFeed{items: slices.Clone(items), failAt: tc.failAt}
+			core.onRequest = func(offset int) {
+				if tc.unsubscribeAt != 0 && offset == tc.unsubscribeAt {
+					require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceGRPC, dao, UnsubscribeFreeze))
+				}
+				if tc.endAt != 0 && offset == tc.endAt {
+					core.items = core.items[1:]
+				}
+			}
+			maxAttempts := 2
+			if tc.status == BackfillFailed {
+				maxAttempts = 1
+			}
+			backfiller := NewBackfiller(store, store, core, config.Backfill{
+				Concurrency: 1,
+				PageSize:    10,
+				MaxAttempts: maxAttempts,
+			})
+
+			_, err := backfiller.Enqueue(ctx, subscriber, dao)
+			require.NoError(t, err)
+
+			for range 2 {
+				jobs, err := store.ClaimBackfills(ctx, 1, time.Now().Add(-time.Minute))
+				require.NoError(t, err)
+				for _, job := range jobs {
+					backfiller.run(ctx, &job)
+				}
+			}
+
+			jobs, err := backfiller.Find(ctx, subscriber, dao)
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			assert.Equal(t, tc.status, jobs[0].Status)
+			assert.Equal(t, tc.requests, core.requests)
+
+			// every proposal is stored even if the core feed changes between pages
+			if tc.status == BackfillCompleted {
+				assert.Equal(t, len(core.items), jobs[0].Processed)
+				assert.Equal(t, len(core.items), jobs[0].Total)
+				assertCount(t, store, 25, FilterBySubscriberID(subscriber))
+			}
+		})
+	}
+}
+
+func TestBackfiller_Backoff(t *testing.T) {
+	backfiller := NewBackfiller(nil, nil, nil, config.Backfill{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+	})
+
+	for attempts, expected := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+		9: 5 * time.Second,
+	} {
+		assert.Equal(t, expected, backfiller.backoff(attempts))
+	}
+}
+
+func TestBackfiller_BackfillMany(t *testing.T) {
+	ctx := context.Background()
+	subscriber := uuid.New()
+	daos := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	items := make([]feed.Item, 0, 15)
+	for i := range 15 {
+		// the last dao has no active proposals
+		items = append(items, feed.Item{
+			ID:         uuid.New(),
+			DaoID:      daos[i%2],
+			ProposalID: uuid.NewString(),
+			Type:       "proposal",
+			Action:     "proposal.created",
+			Snapshot:   storeTestSnapshot(ProposalStateActive, int64(i)),
+			Timeline:   []byte(`[]`),
+		})
+	}
+
+	for name, tc := range map[string]struct {
+		failAt   int
+		status   BackfillStatus
+		stored   int64
+		requests []int
+	}{
+		"single batch": {
+			failAt:   -1,
+			status:   BackfillCompleted,
+			stored:   15,
+			requests: []int{0, 9},
+		},
+		"fallback to jobs": {
+			failAt:   9,
+			status:   BackfillPending,
+			requests: []int{0, 9},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := NewMemoryStore(UpdatePolicy{})
+			core := &fakeCoreFeed{items: items, failAt: tc.failAt}
+			backfiller := NewBackfiller(store, store, core, config.Backfill{
+				Concurrency: 1,
+				PageSize:    10,
+				MaxAttempts: 1,
+			})
+
+			jobs, err := backfiller.BackfillMany(ctx, subscriber, append(daos, daos[0]))
+			require.NoError(t, err)
+			require.Len(t, jobs, len(daos))
+			assert.Equal(t, tc.requests, core.requests)
+			assertCount(t, store, tc.stored, FilterBySubscriberID(subscriber))
+
+			stored, err := backfiller.Find(ctx, subscriber, uuid.Nil)
+			require.NoError(t, err)
+			require.Len(t, stored, len(daos))
+
+			processed := make(map[uuid.UUID]int)
+			for _, job := range stored {
+				assert.Equal(t, tc.status, job.Status)
+				processed[job.DaoID] = job.Processed
+			}
+
+			if tc.status == BackfillCompleted {
+				assert.Equal(t, map[uuid.UUID]int{daos[0]: 8, daos[1]: 7, daos[2]: 0}, processed)
+			}
+		})
+	}
+}
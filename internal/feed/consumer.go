@@ -2,18 +2,34 @@ package feed
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/goverland-labs/goverland-platform-events/events/inbox"
 	client "github.com/goverland-labs/goverland-platform-events/pkg/natsclient"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 
 	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/natsconsumer"
 )
 
+// SubjectSubscriptionDeleted is published when the user leaves the dao. It isn't a part of goverland-platform-events,
+// the subject and UnsubscribePayload are defined by this service, publishers have to follow them.
+const SubjectSubscriptionDeleted = "inbox.subscription.deleted"
+
+// UnsubscribePayload is the payload of SubjectSubscriptionDeleted.
+type UnsubscribePayload struct {
+	SubscriberID uuid.UUID `json:"subscriber_id"`
+	DaoID        uuid.UUID `json:"dao_id"`
+	// Mode is purge, archive or freeze, empty value means DefaultUnsubscribeMode
+	Mode string `json:"mode,omitempty"`
+}
+
 const (
 	maxPendingElements = 100
 	rateLimit          = 500 * client.KiB
@@ -25,40 +41,52 @@ type closable interface {
 }
 
 type Consumer struct {
-	conn      *nats.Conn
-	consumers []closable
-	service   *Service
+	conn       *nats.Conn
+	consumers  []closable
+	service    *Service
+	deadLetter natsconsumer.Publisher
+	cfg        config.Consumer
 }
 
-func NewConsumer(conn *nats.Conn, service *Service) *Consumer {
+func NewConsumer(conn *nats.Conn, service *Service, deadLetter natsconsumer.Publisher, cfg config.Consumer) *Consumer {
 	return &Consumer{
-		conn:    conn,
-		service: service,
+		conn:       conn,
+		service:    service,
+		deadLetter: deadLetter,
+		cfg:        cfg,
 	}
 }
 
 func (c *Consumer) Start(ctx context.Context) error {
 	group := config.GenerateGroupName("inbox_feed")
 
-	opts := []client.ConsumerOpt{
-		client.WithRateLimit(rateLimit),
-		client.WithMaxAckPending(maxPendingElements),
-		client.WithAckWait(executionTtl),
+	handlers := map[string]natsconsumer.Handler{
+		inbox.SubjectFeedUpdated:         natsconsumer.JSONHandler(c.handler()),
+		inbox.SubjectVoteCreated:         natsconsumer.JSONHandler(c.handlerVoteCreated()),
+		inbox.SubjectFeedSettingsUpdated: natsconsumer.JSONHandler(c.handlerSettingsUpdated()),
+		SubjectSubscriptionDeleted:       natsconsumer.JSONHandler(c.handlerUnsubscribed()),
 	}
 
-	cfu, err := client.NewConsumer(ctx, c.conn, group, inbox.SubjectFeedUpdated, c.handler(), opts...)
-	if err != nil {
-		return fmt.Errorf("consume for %s/%s: %w", group, inbox.SubjectFeedUpdated, err)
-	}
-	cvc, err := client.NewConsumer(ctx, c.conn, group, inbox.SubjectVoteCreated, c.handlerVoteCreated(), opts...)
-	if err != nil {
-		return fmt.Errorf("consume for %s/%s: %w", group, inbox.SubjectFeedUpdated, err)
-	}
-	fcc, err := client.NewConsumer(ctx, c.conn, group, inbox.SubjectFeedSettingsUpdated, c.handlerSettingsUpdated(), opts...)
-	if err != nil {
-		return fmt.Errorf("consume for %s/%s: %w", group, inbox.SubjectFeedSettingsUpdated, err)
+	for subject, h := range handlers {
+		cs, err := natsconsumer.New(ctx, c.conn, natsconsumer.Config{
+			Group:             group,
+			Subject:           subject,
+			DeadLetterSubject: c.cfg.DeadLetterSubject,
+			Retry: natsconsumer.RetryPolicy{
+				MaxDeliver:     c.cfg.MaxDeliver,
+				InitialBackoff: c.cfg.InitialBackoff,
+				MaxBackoff:     c.cfg.MaxBackoff,
+			},
+			AckWait:       executionTtl,
+			MaxAckPending: maxPendingElements,
+			RateLimit:     rateLimit,
+		}, h, c.deadLetter)
+		if err != nil {
+			return fmt.Errorf("consume for %s/%s: %w", group, subject, err)
+		}
+
+		c.consumers = append(c.consumers, cs)
 	}
-	c.consumers = append(c.consumers, cfu, cvc, fcc)
 
 	log.Info().Msg("feed consumer is started")
 
@@ -67,22 +95,37 @@ func (c *Consumer) Start(ctx context.Context) error {
 	return c.stop()
 }
 
+// stop drains all consumers in parallel, so in-flight messages are processed before the shutdown.
 func (c *Consumer) stop() error {
+	var wg sync.WaitGroup
 	for _, cs := range c.consumers {
-		if err := cs.Close(); err != nil {
-			log.Error().Err(err).Msg("close feed consumer")
-		}
+		wg.Add(1)
+		go func(cs closable) {
+			defer wg.Done()
+
+			if err := cs.Close(); err != nil {
+				log.Error().Err(err).Msg("close feed consumer")
+			}
+		}(cs)
 	}
+	wg.Wait()
+
+	log.Info().Msg("feed consumer is stopped")
 
 	return nil
 }
 
-func (c *Consumer) handler() inbox.FeedHandler {
-	return func(payload inbox.FeedPayload) error {
+func (c *Consumer) handler() func(ctx context.Context, payload inbox.FeedPayload) error {
+	return func(ctx context.Context, payload inbox.FeedPayload) error {
 		converted := convertPayloadToInternal(payload)
 
-		if err := c.service.Process(context.TODO(), converted); err != nil {
+		if err := c.service.Process(ctx, converted); err != nil {
 			log.Error().Err(err).Msgf("process item: %s", converted.ID)
+
+			if errors.Is(err, ErrInvalidSnapshot) {
+				return natsconsumer.Permanent(err)
+			}
+
 			return err
 		}
 
@@ -90,9 +133,18 @@ func (c *Consumer) handler() inbox.FeedHandler {
 	}
 }
 
-func (c *Consumer) handlerVoteCreated() inbox.VoteHandler {
-	return func(payload inbox.VotePayload) error {
-		if err := c.service.TryAutoarchive(context.TODO(), payload.UserID, payload.ProposalID); err != nil {
+func (c *Consumer) handlerVoteCreated() func(ctx context.Context, payload inbox.VotePayload) error {
+	return func(ctx context.Context, payload inbox.VotePayload) error {
+		if payload.UserID == uuid.Nil {
+			return natsconsumer.Permanent(errors.New("empty user id"))
+		}
+
+		if err := c.service.MarkAsVoted(ctx, payload.UserID, payload.ProposalID); err != nil {
+			log.Error().Err(err).Msgf("mark as voted: %s", payload.UserID)
+			return err
+		}
+
+		if err := c.service.TryAutoarchive(ctx, payload.UserID, payload.ProposalID); err != nil {
 			log.Error().Err(err).Msgf("process voting: %s", payload.UserID)
 			return err
 		}
@@ -101,9 +153,13 @@ func (c *Consumer) handlerVoteCreated() inbox.VoteHandler {
 	}
 }
 
-func (c *Consumer) handlerSettingsUpdated() inbox.FeedSettingsHandler {
-	return func(payload inbox.FeedSettingsPayload) error {
-		if err := c.service.SaveSettings(context.TODO(), payload.SubscriberID, payload.AutoarchiveAfterDays); err != nil {
+func (c *Consumer) handlerSettingsUpdated() func(ctx context.Context, payload inbox.FeedSettingsPayload) error {
+	return func(ctx context.Context, payload inbox.FeedSettingsPayload) error {
+		if payload.SubscriberID == uuid.Nil {
+			return natsconsumer.Permanent(errors.New("empty subscriber id"))
+		}
+
+		if err := c.service.SaveSettings(ctx, payload.SubscriberID, payload.AutoarchiveAfterDays); err != nil {
 			log.Error().Err(err).Msgf("process settings: %s", payload.SubscriberID)
 			return err
 		}
@@ -112,6 +168,26 @@ func (c *Consumer) handlerSettingsUpdated() inbox.FeedSettingsHandler {
 	}
 }
 
+func (c *Consumer) handlerUnsubscribed() func(ctx context.Context, payload UnsubscribePayload) error {
+	return func(ctx context.Context, payload UnsubscribePayload) error {
+		if payload.SubscriberID == uuid.Nil || payload.DaoID == uuid.Nil {
+			return natsconsumer.Permanent(errors.New("empty subscriber or dao id"))
+		}
+
+		mode, err := ParseUnsubscribeMode(payload.Mode)
+		if err != nil {
+			return natsconsumer.Permanent(err)
+		}
+
+		if err = c.service.Unsubscribe(ctx, payload.SubscriberID, SourceSubscriptionConsumer, payload.DaoID, mode); err != nil {
+			log.Error().Err(err).Msgf("process unsubscribe: %s", payload.SubscriberID)
+			return err
+		}
+
+		return nil
+	}
+}
+
 func convertPayloadToInternal(payload inbox.FeedPayload) Item {
 	createdAt := time.Now()
 	if len(payload.Timeline) > 0 {
@@ -140,7 +216,7 @@ func convertPayloadTimelineToInternal(timeline []inbox.TimelineItem) Timeline {
 	for idx, t := range timeline {
 		action := convertPayloadActionToInternal(t.Action)
 
-		if action == ProposalCreated {
+		if action == ProposalCreated || action == DiscussionCreated {
 			createdAt = t.CreatedAt
 			createdIdx = idx
 		}
@@ -187,6 +263,15 @@ func convertPayloadTimelineToInternal(timeline []inbox.TimelineItem) Timeline {
 	return converted
 }
 
+// payloadTypeDiscussion and discussion actions aren't declared by the events package yet
+const (
+	payloadTypeDiscussion inbox.Type = "discussion"
+
+	payloadDiscussionCreated inbox.TimelineAction = "discussion.created"
+	payloadDiscussionUpdated inbox.TimelineAction = "discussion.updated"
+	payloadDiscussionReplied inbox.TimelineAction = "discussion.replied"
+)
+
 var payloadActionMap = map[inbox.TimelineAction]Action{
 	inbox.DaoCreated:                  DaoCreated,
 	inbox.DaoUpdated:                  DaoUpdated,
@@ -197,11 +282,14 @@ var payloadActionMap = map[inbox.TimelineAction]Action{
 	inbox.ProposalVotingStarted:       ProposalVotingStarted,
 	inbox.ProposalVotingQuorumReached: ProposalVotingQuorumReached,
 	inbox.ProposalVotingEnded:         ProposalVotingEnded,
+	payloadDiscussionCreated:          DiscussionCreated,
+	payloadDiscussionUpdated:          DiscussionUpdated,
+	payloadDiscussionReplied:          DiscussionReplied,
 }
 
 func actionWeight(a Action) int {
 	switch a {
-	case DaoCreated, ProposalCreated:
+	case DaoCreated, ProposalCreated, DiscussionCreated:
 		return 1
 	case ProposalVotingQuorumReached:
 		return 3
@@ -228,6 +316,8 @@ func convertPayloadTypeToInternal(t inbox.Type) Type {
 		return Dao
 	case inbox.TypeProposal:
 		return Proposal
+	case payloadTypeDiscussion:
+		return Discussion
 	default:
 	}
 
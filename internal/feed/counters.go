@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"bytes"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+)
+
+// Counters are materialized counters of the subscriber feed. Store write paths change them in the same transaction
+// as items, CountersReconciler fixes the drift if any. Only items matched by countedFilters are taken into account.
+type Counters struct {
+	SubscriberID uuid.UUID `gorm:"primary_key"`
+	UpdatedAt    time.Time
+	// TotalCount is the number of not archived items
+	TotalCount int64
+	// UnreadCount is the number of not archived unread items
+	UnreadCount   int64
+	ArchivedCount int64
+}
+
+// countedFilters returns filters of items which are taken into account in counters.
+// Snoozed items are hidden, so they are counted only after they wake up.
+func countedFilters() []Filter {
+	return []Filter{
+		SkipSpammed(),
+		SkipCanceled(),
+		FilterBySnoozedStatus(helpers.Ptr(false)),
+	}
+}
+
+// countedQuery matches items in the same way as countedFilters do in the database.
+var countedQuery = newMemoryQuery(countedFilters())
+
+// countersDelta accumulates changes of counters by subscribers.
+type countersDelta map[uuid.UUID]*Counters
+
+// change takes into account the item state before and after the change, nil means the item doesn't exist.
+func (d countersDelta) change(before, after *Item) {
+	if before != nil {
+		d.add(before, -1)
+	}
+
+	if after != nil {
+		d.add(after, 1)
+	}
+}
+
+func (d countersDelta) add(item *Item, sign int64) {
+	if !countedQuery.match(item) {
+		return
+	}
+
+	c, ok := d[item.SubscriberID]
+	if !ok {
+		c = &Counters{SubscriberID: item.SubscriberID}
+		d[item.SubscriberID] = c
+	}
+
+	if item.ArchivedAt != nil {
+		c.ArchivedCount += sign
+		return
+	}
+
+	c.TotalCount += sign
+	if item.ReadAt == nil {
+		c.UnreadCount += sign
+	}
+}
+
+// list returns not empty changes sorted by subscriber, so counters are always locked in the same order.
+func (d countersDelta) list() []Counters {
+	list := make([]Counters, 0, len(d))
+	for _, c := range d {
+		if c.TotalCount == 0 && c.UnreadCount == 0 && c.ArchivedCount == 0 {
+			continue
+		}
+
+		list = append(list, *c)
+	}
+
+	slices.SortFunc(list, func(a, b Counters) int {
+		return bytes.Compare(a.SubscriberID[:], b.SubscriberID[:])
+	})
+
+	return list
+}
+
+// sameCounts reports if counters contain the same values.
+func sameCounts(a, b Counters) bool {
+	return a.TotalCount == b.TotalCount && a.UnreadCount == b.UnreadCount && a.ArchivedCount == b.ArchivedCount
+}
@@ -0,0 +1,82 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	countersReconcileBatchSize = 500
+	countersReconcileDelay     = time.Hour
+)
+
+// CountersStore gives access to materialized counters of subscriber feeds.
+type CountersStore interface {
+	// ReconcileCounters recalculates counters of up to limit subscribers following the given one in the id order
+	// and fixes the drifted ones. It returns processed subscribers and fixed counters.
+	ReconcileCounters(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, []Counters, error)
+}
+
+// CountersReconciler periodically recalculates counters of all subscribers, so the drift
+// caused by bugs or manual changes of items doesn't live long.
+type CountersReconciler struct {
+	store CountersStore
+}
+
+func NewCountersReconciler(store CountersStore) *CountersReconciler {
+	return &CountersReconciler{
+		store: store,
+	}
+}
+
+func (r *CountersReconciler) Start(ctx context.Context) error {
+	for {
+		start := time.Now()
+		fixed, err := r.reconcile(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("reconcile feed counters")
+		}
+
+		log.Debug().Int("fixed", fixed).Msgf("reconcile feed counters completed: %v", time.Since(start))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(countersReconcileDelay):
+		}
+	}
+}
+
+// reconcile walks through all subscribers by batches and returns the number of fixed counters.
+func (r *CountersReconciler) reconcile(ctx context.Context) (int, error) {
+	var (
+		after uuid.UUID
+		fixed int
+	)
+
+	for {
+		subscribers, drifted, err := r.store.ReconcileCounters(ctx, after, countersReconcileBatchSize)
+		if err != nil {
+			return fixed, err
+		}
+
+		for _, c := range drifted {
+			log.Warn().
+				Str("subscriber_id", c.SubscriberID.String()).
+				Int64("total_count", c.TotalCount).
+				Int64("unread_count", c.UnreadCount).
+				Int64("archived_count", c.ArchivedCount).
+				Msg("feed counters were fixed")
+		}
+		fixed += len(drifted)
+
+		if len(subscribers) < countersReconcileBatchSize {
+			return fixed, nil
+		}
+
+		after = subscribers[len(subscribers)-1]
+	}
+}
@@ -0,0 +1,34 @@
+package feed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountersReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	drifted, actual, orphan := uuid.New(), uuid.New(), uuid.New()
+	storeTestItems(t, store, drifted, 3)
+	storeTestItems(t, store, actual, 2)
+	store.counters[drifted] = Counters{SubscriberID: drifted, TotalCount: 5, UnreadCount: 1}
+	store.counters[orphan] = Counters{SubscriberID: orphan, ArchivedCount: 2}
+
+	reconciler := NewCountersReconciler(store)
+
+	fixed, err := reconciler.reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fixed)
+
+	for _, subscriber := range []uuid.UUID{drifted, actual, orphan} {
+		assertCounters(t, store, subscriber)
+	}
+
+	fixed, err = reconciler.reconcile(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, fixed)
+}
@@ -0,0 +1,70 @@
+package feed
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points to the position of the item in the SortedByActuality order.
+// Clients get it as an opaque string and must not rely on its content.
+type Cursor struct {
+	Rank    int       `json:"r"`
+	Created int64     `json:"c"`
+	ID      uuid.UUID `json:"i"`
+}
+
+// NewCursor returns the cursor of the item, it's built from the snapshot in the same way as the SQL ordering does.
+func NewCursor(item Item) (Cursor, error) {
+	if !json.Valid(item.Snapshot) {
+		return Cursor{}, errors.New("invalid snapshot")
+	}
+
+	return actualityCursor(&item), nil
+}
+
+func (c Cursor) Encode() string {
+	// marshaling of the plain struct never fails
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (c Cursor) Validate() error {
+	if c.Rank < 1 || c.Rank > len(actualityProposalStates)+1 {
+		return fmt.Errorf("%w: rank out of range", ErrInvalidCursor)
+	}
+
+	if c.Created < 0 {
+		return fmt.Errorf("%w: negative created", ErrInvalidCursor)
+	}
+
+	if c.ID == uuid.Nil {
+		return fmt.Errorf("%w: empty id", ErrInvalidCursor)
+	}
+
+	return nil
+}
+
+func DecodeCursor(raw string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	if err = c.Validate(); err != nil {
+		return Cursor{}, err
+	}
+
+	return c, nil
+}
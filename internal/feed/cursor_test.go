@@ -0,0 +1,100 @@
+package feed
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCursor(t *testing.T) {
+	id := uuid.New()
+
+	for name, tc := range map[string]struct {
+		snapshot string
+		cursor   Cursor
+	}{
+		"active proposal": {
+			snapshot: `{"state":"active","created":1700000000}`,
+			cursor:   Cursor{Rank: 1, Created: 1700000000, ID: id},
+		},
+		"canceled proposal": {
+			snapshot: `{"state":"canceled","created":1700000001}`,
+			cursor:   Cursor{Rank: 6, Created: 1700000001, ID: id},
+		},
+		"unknown state": {
+			snapshot: `{"state":"closed","created":1}`,
+			cursor:   Cursor{Rank: 7, Created: 1, ID: id},
+		},
+		"without created": {
+			snapshot: `{"state":"pending"}`,
+			cursor:   Cursor{Rank: 2, ID: id},
+		},
+		"float created": {
+			snapshot: `{"state":"pending","created":1700000000.5}`,
+			cursor:   Cursor{Rank: 2, ID: id},
+		},
+		"text created": {
+			snapshot: `{"state":"failed","created":"yesterday"}`,
+			cursor:   Cursor{Rank: 4, ID: id},
+		},
+		"numeric text created": {
+			snapshot: `{"state":"failed","created":"1700000000"}`,
+			cursor:   Cursor{Rank: 4, Created: 1700000000, ID: id},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actual, err := NewCursor(Item{ID: id, Snapshot: []byte(tc.snapshot)})
+			require.NoError(t, err)
+			assert.Equal(t, tc.cursor, actual)
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	valid := Cursor{Rank: 3, Created: 1700000000, ID: uuid.New()}
+
+	for name, tc := range map[string]struct {
+		raw    string
+		cursor Cursor
+		err    bool
+	}{
+		"encoded cursor": {
+			raw:    valid.Encode(),
+			cursor: valid,
+		},
+		"not base64": {
+			raw: "!!!",
+			err: true,
+		},
+		"not json": {
+			raw: base64.RawURLEncoding.EncodeToString([]byte("cursor")),
+			err: true,
+		},
+		"rank out of range": {
+			raw: Cursor{Rank: 8, ID: valid.ID}.Encode(),
+			err: true,
+		},
+		"empty id": {
+			raw: Cursor{Rank: 1, Created: 1}.Encode(),
+			err: true,
+		},
+		"negative created": {
+			raw: Cursor{Rank: 1, Created: -1, ID: valid.ID}.Encode(),
+			err: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actual, err := DecodeCursor(tc.raw)
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidCursor)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.cursor, actual)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SubjectItemCreated      = "inbox.feed.item.created"
+	SubjectItemUpdated      = "inbox.feed.item.updated"
+	SubjectItemRead         = "inbox.feed.item.read"
+	SubjectItemUnread       = "inbox.feed.item.unread"
+	SubjectItemArchived     = "inbox.feed.item.archived"
+	SubjectItemUnarchived   = "inbox.feed.item.unarchived"
+	SubjectItemAutoArchived = "inbox.feed.item.auto_archived"
+	SubjectItemResurfaced   = "inbox.feed.item.resurfaced"
+	SubjectItemDeleted      = "inbox.feed.item.deleted"
+	SubjectItemSnoozed      = "inbox.feed.item.snoozed"
+	SubjectItemUnsnoozed    = "inbox.feed.item.unsnoozed"
+
+	// subjectItemEvents matches subjects of all feed item events
+	subjectItemEvents = "inbox.feed.item.*"
+)
+
+// ItemEventVersion is the version of the ItemEvent payload. It has to be increased on every
+// not backward compatible change of the payload.
+const ItemEventVersion = 1
+
+// ItemEvent is the payload of feed item lifecycle events.
+type ItemEvent struct {
+	Version int `json:"version"`
+	// EventID is the id of the outbox event, it grows with every stored event and is set by OutboxRelay
+	EventID      int64     `json:"event_id,omitempty"`
+	ID           uuid.UUID `json:"id"`
+	SubscriberID uuid.UUID `json:"subscriber_id"`
+	DaoID        uuid.UUID `json:"dao_id"`
+	ProposalID   string    `json:"proposal_id"`
+	// DiscussionID is set for discussion items
+	DiscussionID string    `json:"discussion_id,omitempty"`
+	Action       Action    `json:"action,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+	// ResurfacedReason is set for resurfaced items only, see ResurfacePolicy.Reason
+	ResurfacedReason string `json:"resurfaced_reason,omitempty"`
+	// UnarchivedReason is set for unarchived items only: manual or the action which unarchived the item
+	UnarchivedReason string `json:"unarchived_reason,omitempty"`
+	// ArchiveReason is set for archived and auto-archived items only
+	ArchiveReason ArchiveReason `json:"archive_reason,omitempty"`
+}
+
+// OutboxEvent is the event stored in the same transaction as the change of feed items.
+// OutboxRelay publishes stored events to NATS.
+type OutboxEvent struct {
+	ID        int64 `gorm:"primaryKey"`
+	CreatedAt time.Time
+	Subject   string
+	Payload   json.RawMessage `gorm:"type:jsonb"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+// published returns the payload with the id of the event, the id isn't known when the payload is stored.
+func (e OutboxEvent) published() (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(e.Payload, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal %s event: %w", e.Subject, err)
+	}
+
+	fields["event_id"] = json.RawMessage(strconv.FormatInt(e.ID, 10))
+
+	return json.Marshal(fields)
+}
+
+func newItemEvents(subject string, items []Item, occurredAt time.Time) ([]OutboxEvent, error) {
+	events := make([]OutboxEvent, 0, len(items))
+	for _, item := range items {
+		event := ItemEvent{
+			Version:      ItemEventVersion,
+			ID:           item.ID,
+			SubscriberID: item.SubscriberID,
+			DaoID:        item.DaoID,
+			ProposalID:   item.ProposalID,
+			DiscussionID: item.DiscussionID,
+			Action:       item.Action,
+			OccurredAt:   occurredAt,
+		}
+		switch subject {
+		case SubjectItemResurfaced:
+			event.ResurfacedReason = item.ResurfacedReason
+		case SubjectItemUnarchived:
+			event.UnarchivedReason = item.UnarchivedReason
+		case SubjectItemArchived, SubjectItemAutoArchived:
+			event.ArchiveReason = item.ArchiveReason
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s event: %w", subject, err)
+		}
+
+		events = append(events, OutboxEvent{
+			CreatedAt: occurredAt,
+			Subject:   subject,
+			Payload:   payload,
+		})
+	}
+
+	return events, nil
+}
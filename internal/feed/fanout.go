@@ -0,0 +1,213 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidSnapshot means the item can't be processed until the snapshot is fixed.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// fanoutStore is the part of the storage used by the fan-out pipeline.
+type fanoutStore interface {
+	FindSubscribersByProposalID(ctx context.Context, proposalID string) ([]uuid.UUID, error)
+	FindSubscribersByDiscussionID(ctx context.Context, discussionID string) ([]uuid.UUID, error)
+	FindSubscribersByDaoItem(ctx context.Context, daoID uuid.UUID) ([]uuid.UUID, error)
+	FindDaoEventsSubscribers(ctx context.Context, subscriberIDs []uuid.UUID) ([]uuid.UUID, error)
+	BulkCreateOrUpdate(ctx context.Context, items []Item) error
+}
+
+// FanoutReport describes the outcome of every stage of the fan-out pipeline.
+type FanoutReport struct {
+	// Updated is the number of subscribers which already had the item and got it updated
+	Updated int
+	// Eligible shows if the item could be added to subscribers which don't have it yet
+	Eligible bool
+	// Created is the number of DAO subscribers which got the item for the first time
+	Created int
+	// Invalid is the number of DAO subscribers skipped due to the malformed id
+	Invalid int
+	// Subscribers whose feed got the item updated or created
+	Subscribers []uuid.UUID
+}
+
+// fanout delivers the feed item to subscribers in the following stages:
+//   - update the item for subscribers which already have it, regardless of the subscription;
+//   - decide if the item is eligible for the new subscribers, only active proposals, discussions and DAO-level items are;
+//   - insert the item for the rest of the DAO subscribers, DAO-level items only for subscribers which opted in to them.
+type fanout struct {
+	store         fanoutStore
+	subscriptions SubscriptionsFinder
+}
+
+func newFanout(store fanoutStore, subscriptions SubscriptionsFinder) *fanout {
+	return &fanout{
+		store:         store,
+		subscriptions: subscriptions,
+	}
+}
+
+func (f *fanout) Run(ctx context.Context, item Item) (FanoutReport, error) {
+	var report FanoutReport
+
+	holders, err := f.updateHolders(ctx, item)
+	if err != nil {
+		return report, err
+	}
+	report.Updated = len(holders)
+
+	report.Eligible, err = eligibleForNewSubscribers(item)
+	if err != nil {
+		return report, err
+	}
+
+	if report.Eligible {
+		report.Created, report.Invalid, err = f.insertForNewSubscribers(ctx, item, holders)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	report.Subscribers = make([]uuid.UUID, 0, len(holders))
+	for subscriberID := range holders {
+		report.Subscribers = append(report.Subscribers, subscriberID)
+	}
+
+	return report, nil
+}
+
+// updateHolders updates the item for all subscribers which have it and returns them.
+func (f *fanout) updateHolders(ctx context.Context, item Item) (map[uuid.UUID]struct{}, error) {
+	subscribers, err := f.findHolders(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make(map[uuid.UUID]struct{}, len(subscribers))
+	list := make([]Item, 0, len(subscribers))
+	for _, subscriberID := range subscribers {
+		holders[subscriberID] = struct{}{}
+		list = append(list, personalize(item, subscriberID))
+	}
+
+	if err = f.store.BulkCreateOrUpdate(ctx, list); err != nil {
+		return nil, fmt.Errorf("unable to update feed item '%s' for %d subscribers: %w", item.ID, len(list), err)
+	}
+
+	return holders, nil
+}
+
+func (f *fanout) findHolders(ctx context.Context, item Item) ([]uuid.UUID, error) {
+	if item.DAO() {
+		subscribers, err := f.store.FindSubscribersByDaoItem(ctx, item.DaoID)
+		if err != nil {
+			return nil, fmt.Errorf("find subscribers by dao item: %w", err)
+		}
+
+		return subscribers, nil
+	}
+
+	if item.Type == Discussion {
+		subscribers, err := f.store.FindSubscribersByDiscussionID(ctx, item.DiscussionID)
+		if err != nil {
+			return nil, fmt.Errorf("find subscribers by discussion id: %w", err)
+		}
+
+		return subscribers, nil
+	}
+
+	subscribers, err := f.store.FindSubscribersByProposalID(ctx, item.ProposalID)
+	if err != nil {
+		return nil, fmt.Errorf("find subscribers by proposal id: %w", err)
+	}
+
+	return subscribers, nil
+}
+
+// eligibleForNewSubscribers checks if the item has to be added to the feed of DAO subscribers.
+// Completed proposals are not interesting for subscribers who haven't seen them before,
+// discussions and DAO-level items don't have the state and are always eligible.
+func eligibleForNewSubscribers(item Item) (bool, error) {
+	if item.DAO() || item.Type == Discussion {
+		if !json.Valid(item.Snapshot) {
+			return false, fmt.Errorf("%w: malformed %s snapshot", ErrInvalidSnapshot, item.Type)
+		}
+
+		return true, nil
+	}
+
+	var info ShortProposalInfo
+	if err := json.Unmarshal(item.Snapshot, &info); err != nil {
+		return false, fmt.Errorf("%w: %s", ErrInvalidSnapshot, err)
+	}
+
+	return info.Active(), nil
+}
+
+// insertForNewSubscribers adds the item to DAO subscribers which are not in the holders list
+// and extends the list with them. Returns the number of created and skipped due to the invalid id items.
+func (f *fanout) insertForNewSubscribers(ctx context.Context, item Item, holders map[uuid.UUID]struct{}) (created, invalid int, err error) {
+	resp, err := f.subscriptions.FindSubscribers(ctx, &inboxapi.FindSubscribersRequest{
+		DaoId: item.DaoID.String(),
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("find subscribers: %w", err)
+	}
+
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+
+	candidates := make([]uuid.UUID, 0, len(resp.GetUsers()))
+	for _, sub := range resp.GetUsers() {
+		subscriberID, err := uuid.Parse(sub.GetUserId())
+		if err != nil {
+			log.Warn().Err(err).Str("user_id", sub.GetUserId()).Msg("skip subscriber with invalid id")
+			invalid++
+
+			continue
+		}
+
+		if _, ok := holders[subscriberID]; ok {
+			continue
+		}
+
+		candidates = append(candidates, subscriberID)
+	}
+
+	if item.DAO() && len(candidates) > 0 {
+		if candidates, err = f.store.FindDaoEventsSubscribers(ctx, candidates); err != nil {
+			return 0, invalid, fmt.Errorf("find dao events subscribers: %w", err)
+		}
+	}
+
+	list := make([]Item, 0, len(candidates))
+	for _, subscriberID := range candidates {
+		if _, ok := holders[subscriberID]; ok {
+			continue
+		}
+
+		holders[subscriberID] = struct{}{}
+		list = append(list, personalize(item, subscriberID))
+	}
+
+	if err = f.store.BulkCreateOrUpdate(ctx, list); err != nil {
+		return 0, invalid, fmt.Errorf("unable to save feed item '%s' for %d subscribers: %w", item.ID, len(list), err)
+	}
+
+	return len(list), invalid, nil
+}
+
+func personalize(item Item, subscriberID uuid.UUID) Item {
+	personalized := item
+	personalized.SubscriberID = subscriberID
+
+	return personalized
+}
@@ -0,0 +1,217 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type fakeSubscriptions struct {
+	mu          sync.Mutex
+	subscribers map[string][]string
+	err         error
+	calls       int
+}
+
+func (f *fakeSubscriptions) FindSubscribers(_ context.Context, in *inboxapi.FindSubscribersRequest, _ ...grpc.CallOption) (*inboxapi.UserList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	list := &inboxapi.UserList{}
+	for _, id := range f.subscribers[in.GetDaoId()] {
+		list.Users = append(list.Users, &inboxapi.UserID{UserId: id})
+	}
+
+	return list, nil
+}
+
+func (f *fakeSubscriptions) ListSubscriptions(context.Context, *inboxapi.ListSubscriptionRequest, ...grpc.CallOption) (*inboxapi.ListSubscriptionResponse, error) {
+	return &inboxapi.ListSubscriptionResponse{}, nil
+}
+
+func newMemoryStoreWith(t *testing.T, items ...Item) *MemoryStore {
+	store := NewMemoryStore(UpdatePolicy{})
+	require.NoError(t, store.BulkCreateOrUpdate(context.Background(), items))
+
+	return store
+}
+
+func proposalActions(t *testing.T, store FeedStore, proposalID string) map[uuid.UUID]Action {
+	list, err := store.FindByFilters(context.Background(), []Filter{FilterByProposalID(proposalID)})
+	require.NoError(t, err)
+
+	actions := make(map[uuid.UUID]Action)
+	for _, item := range list {
+		actions[item.SubscriberID] = item.Action
+	}
+
+	return actions
+}
+
+func TestFanout_Run(t *testing.T) {
+	dao := uuid.New()
+	holder, unsubscribed := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	activeItem := Item{ID: uuid.New(), DaoID: dao, ProposalID: "proposal", Action: ProposalUpdated, Snapshot: []byte(`{"state":"active"}`)}
+	closedItem := Item{ID: uuid.New(), DaoID: dao, ProposalID: "proposal", Action: ProposalVotingEnded, Snapshot: []byte(`{"state":"closed"}`)}
+	stored := func(subscriberID uuid.UUID) Item {
+		return Item{ID: activeItem.ID, SubscriberID: subscriberID, DaoID: dao, ProposalID: "proposal", Action: ProposalCreated}
+	}
+
+	for name, tc := range map[string]struct {
+		stored      []Item
+		subscribers []string
+		item        Item
+		report      FanoutReport
+		actions     map[uuid.UUID]Action
+	}{
+		"active item for new subscribers": {
+			subscribers: []string{first.String(), second.String()},
+			item:        activeItem,
+			report:      FanoutReport{Eligible: true, Created: 2},
+			actions:     map[uuid.UUID]Action{first: ProposalUpdated, second: ProposalUpdated},
+		},
+		"active item for holders and new subscribers": {
+			stored:      []Item{stored(holder), stored(unsubscribed)},
+			subscribers: []string{holder.String(), first.String()},
+			item:        activeItem,
+			report:      FanoutReport{Updated: 2, Eligible: true, Created: 1},
+			actions:     map[uuid.UUID]Action{holder: ProposalUpdated, unsubscribed: ProposalUpdated, first: ProposalUpdated},
+		},
+		"closed item updated for holders only": {
+			stored:      []Item{stored(holder)},
+			subscribers: []string{holder.String(), first.String(), second.String()},
+			item:        closedItem,
+			report:      FanoutReport{Updated: 1},
+			actions:     map[uuid.UUID]Action{holder: ProposalVotingEnded},
+		},
+		"invalid subscriber doesn't stop the fan-out": {
+			subscribers: []string{"invalid", first.String(), second.String()},
+			item:        activeItem,
+			report:      FanoutReport{Eligible: true, Created: 2, Invalid: 1},
+			actions:     map[uuid.UUID]Action{first: ProposalUpdated, second: ProposalUpdated},
+		},
+		"duplicated subscribers": {
+			subscribers: []string{first.String(), first.String()},
+			item:        activeItem,
+			report:      FanoutReport{Eligible: true, Created: 1},
+			actions:     map[uuid.UUID]Action{first: ProposalUpdated},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newMemoryStoreWith(t, tc.stored...)
+			subscriptions := &fakeSubscriptions{subscribers: map[string][]string{dao.String(): tc.subscribers}}
+
+			report, err := newFanout(store, subscriptions).Run(context.Background(), tc.item)
+			require.NoError(t, err)
+
+			var touched []uuid.UUID
+			for subscriberID := range tc.actions {
+				touched = append(touched, subscriberID)
+			}
+			assert.ElementsMatch(t, touched, report.Subscribers)
+
+			report.Subscribers = nil
+			assert.Equal(t, tc.report, report)
+			assert.Equal(t, tc.actions, proposalActions(t, store, "proposal"))
+		})
+	}
+}
+
+func TestFanout_Discussion(t *testing.T) {
+	dao := uuid.New()
+	holder, first := uuid.New(), uuid.New()
+
+	// the proposal refers to the discussion, but it's the different feed item
+	proposal := Item{ID: uuid.New(), SubscriberID: holder, DaoID: dao, ProposalID: "proposal", DiscussionID: "discussion", Type: Proposal, Action: ProposalCreated}
+	stored := Item{ID: uuid.New(), SubscriberID: holder, DaoID: dao, DiscussionID: "discussion", Type: Discussion, Action: DiscussionCreated}
+	store := newMemoryStoreWith(t, proposal, stored)
+	subscriptions := &fakeSubscriptions{subscribers: map[string][]string{dao.String(): {holder.String(), first.String()}}}
+
+	item := Item{ID: stored.ID, DaoID: dao, DiscussionID: "discussion", Type: Discussion, Action: DiscussionReplied, Snapshot: []byte(`{}`)}
+	report, err := newFanout(store, subscriptions).Run(context.Background(), item)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{holder, first}, report.Subscribers)
+
+	report.Subscribers = nil
+	assert.Equal(t, FanoutReport{Updated: 1, Eligible: true, Created: 1}, report)
+	assert.Equal(t, map[uuid.UUID]Action{holder: ProposalCreated}, proposalActions(t, store, "proposal"))
+
+	list, err := store.FindByFilters(context.Background(), []Filter{FilterByType(Discussion)})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, item := range list {
+		assert.Equal(t, DiscussionReplied, item.Action)
+	}
+}
+
+func TestFanout_DaoItem(t *testing.T) {
+	ctx := context.Background()
+	dao := uuid.New()
+	optedIn, other := uuid.New(), uuid.New()
+
+	store := NewMemoryStore(UpdatePolicy{})
+	require.NoError(t, store.SetDaoEvents(ctx, optedIn, true))
+	subscriptions := &fakeSubscriptions{subscribers: map[string][]string{dao.String(): {optedIn.String(), other.String()}}}
+
+	created := Item{ID: uuid.New(), DaoID: dao, Type: Dao, Action: DaoCreated, Snapshot: []byte(`{"name":"dao"}`)}
+	report, err := newFanout(store, subscriptions).Run(ctx, created)
+	require.NoError(t, err)
+	assert.Equal(t, FanoutReport{Eligible: true, Created: 1, Subscribers: []uuid.UUID{optedIn}}, report)
+
+	// repeated updates are collapsed into the single item of the dao
+	updated := Item{ID: uuid.New(), DaoID: dao, Type: Dao, Action: DaoUpdated, Snapshot: []byte(`{"name":"renamed"}`)}
+	report, err = newFanout(store, subscriptions).Run(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, FanoutReport{Updated: 1, Eligible: true, Subscribers: []uuid.UUID{optedIn}}, report)
+
+	list, err := store.FindByFilters(ctx, []Filter{FilterByType(Dao)})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, optedIn, list[0].SubscriberID)
+	assert.Equal(t, created.ID, list[0].ID)
+	assert.Equal(t, DaoUpdated, list[0].Action)
+	assert.JSONEq(t, `{"name":"renamed"}`, string(list[0].Snapshot))
+}
+
+func TestFanout_SkipSubscribersLookupForNotEligible(t *testing.T) {
+	subscriptions := &fakeSubscriptions{err: errors.New("unavailable")}
+	item := Item{ID: uuid.New(), DaoID: uuid.New(), ProposalID: "proposal", Snapshot: []byte(`{"state":"defeated"}`)}
+
+	report, err := newFanout(NewMemoryStore(UpdatePolicy{}), subscriptions).Run(context.Background(), item)
+	require.NoError(t, err)
+	assert.False(t, report.Eligible)
+	assert.Zero(t, subscriptions.calls)
+}
+
+func TestFanout_Errors(t *testing.T) {
+	item := Item{ID: uuid.New(), DaoID: uuid.New(), ProposalID: "proposal", Snapshot: []byte(`{"state":"active"}`)}
+
+	t.Run("subscriptions unavailable", func(t *testing.T) {
+		subscriptions := &fakeSubscriptions{err: errors.New("unavailable")}
+
+		_, err := newFanout(NewMemoryStore(UpdatePolicy{}), subscriptions).Run(context.Background(), item)
+		require.Error(t, err)
+	})
+
+	t.Run("malformed snapshot", func(t *testing.T) {
+		malformed := item
+		malformed.Snapshot = []byte(`{`)
+
+		_, err := newFanout(NewMemoryStore(UpdatePolicy{}), &fakeSubscriptions{}).Run(context.Background(), malformed)
+		require.ErrorIs(t, err, ErrInvalidSnapshot)
+	})
+}
@@ -1,13 +1,24 @@
 package feed
 
 import (
+	"cmp"
 	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-type Filter func(query *gorm.DB) *gorm.DB
+// Filter narrows or orders the list of feed items. Every filter defines the same semantics
+// for the SQL query and for the in-memory storage, so both FeedStore implementations behave equally.
+type Filter struct {
+	db     func(query *gorm.DB) *gorm.DB
+	memory func(query *memoryQuery)
+}
 
 func FilterBySubscriberID(id uuid.UUID) Filter {
 	var (
@@ -15,8 +26,15 @@ func FilterBySubscriberID(id uuid.UUID) Filter {
 		_     = dummy.SubscriberID
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", id))
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", id))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return item.SubscriberID == id
+			})
+		},
 	}
 }
 
@@ -26,8 +44,181 @@ func FilterByProposalID(id string) Filter {
 		_     = dummy.ProposalID
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Where("proposal_id = @proposal_id", sql.Named("proposal_id", id))
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("proposal_id = @proposal_id", sql.Named("proposal_id", id))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return item.ProposalID == id
+			})
+		},
+	}
+}
+
+func FilterByIDs(ids ...uuid.UUID) Filter {
+	var (
+		dummy Item
+		_     = dummy.ID
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("id in @ids", sql.Named("ids", ids))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return slices.Contains(ids, item.ID)
+			})
+		},
+	}
+}
+
+func FilterByProposalIDs(ids ...string) Filter {
+	var (
+		dummy Item
+		_     = dummy.ProposalID
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("proposal_id in @proposal_ids", sql.Named("proposal_ids", ids))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return slices.Contains(ids, item.ProposalID)
+			})
+		},
+	}
+}
+
+func FilterByDiscussionIDs(ids ...string) Filter {
+	var (
+		dummy Item
+		_     = dummy.DiscussionID
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("discussion_id in @discussion_ids", sql.Named("discussion_ids", ids))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return slices.Contains(ids, item.DiscussionID)
+			})
+		},
+	}
+}
+
+func FilterByDaoIDs(ids ...uuid.UUID) Filter {
+	var (
+		dummy Item
+		_     = dummy.DaoID
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("dao_id in @dao_ids", sql.Named("dao_ids", ids))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return slices.Contains(ids, item.DaoID)
+			})
+		},
+	}
+}
+
+func FilterByActions(actions ...Action) Filter {
+	var (
+		dummy Item
+		_     = dummy.Action
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("action in @actions", sql.Named("actions", actions))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return slices.Contains(actions, item.Action)
+			})
+		},
+	}
+}
+
+// FilterByArchiveReasons returns archived items archived by one of the reasons.
+func FilterByArchiveReasons(reasons ...ArchiveReason) Filter {
+	var (
+		dummy Item
+		_     = dummy.ArchiveReason
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("archive_reason in @archive_reasons", sql.Named("archive_reasons", reasons))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return slices.Contains(reasons, item.ArchiveReason)
+			})
+		},
+	}
+}
+
+func FilterByType(t Type) Filter {
+	var (
+		dummy Item
+		_     = dummy.Type
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("type = @type", sql.Named("type", t))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return item.Type == t
+			})
+		},
+	}
+}
+
+func SkipDiscussions() Filter {
+	var (
+		dummy Item
+		_     = dummy.Type
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("type is distinct from @discussion", sql.Named("discussion", Discussion))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return item.Type != Discussion
+			})
+		},
+	}
+}
+
+// FilterByProposalStates keeps items with the proposal in one of the states according to the snapshot.
+func FilterByProposalStates(states ...string) Filter {
+	var (
+		dummy Item
+		_     = dummy.Snapshot // state
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where(`snapshot->>'state' in @states`, sql.Named("states", states))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				state, ok := snapshotText(item, "state")
+
+				return ok && slices.Contains(states, state)
+			})
+		},
 	}
 }
 
@@ -37,16 +228,27 @@ func FilterByArchivedStatus(status *bool) Filter {
 		_     = dummy.ArchivedAt
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		if status == nil {
-			return query
-		}
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			if status == nil {
+				return query
+			}
+
+			if *status {
+				return query.Where("archived_at is not null")
+			}
 
-		if *status {
-			return query.Where("archived_at is not null")
-		}
+			return query.Where("archived_at is null")
+		},
+		memory: func(query *memoryQuery) {
+			if status == nil {
+				return
+			}
 
-		return query.Where("archived_at is null")
+			query.where(func(item *Item) bool {
+				return (item.ArchivedAt != nil) == *status
+			})
+		},
 	}
 }
 
@@ -56,16 +258,57 @@ func FilterByUnarchivedStatus(status *bool) Filter {
 		_     = dummy.UnarchivedAt
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		if status == nil {
-			return query
-		}
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			if status == nil {
+				return query
+			}
+
+			if *status {
+				return query.Where("unarchived_at is not null")
+			}
+
+			return query.Where("unarchived_at is null")
+		},
+		memory: func(query *memoryQuery) {
+			if status == nil {
+				return
+			}
+
+			query.where(func(item *Item) bool {
+				return (item.UnarchivedAt != nil) == *status
+			})
+		},
+	}
+}
+
+func FilterBySnoozedStatus(status *bool) Filter {
+	var (
+		dummy Item
+		_     = dummy.SnoozedAt
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			if status == nil {
+				return query
+			}
+
+			if *status {
+				return query.Where("snoozed_at is not null")
+			}
 
-		if *status {
-			return query.Where("unarchived_at is not null")
-		}
+			return query.Where("snoozed_at is null")
+		},
+		memory: func(query *memoryQuery) {
+			if status == nil {
+				return
+			}
 
-		return query.Where("unarchived_at is null")
+			query.where(func(item *Item) bool {
+				return (item.SnoozedAt != nil) == *status
+			})
+		},
 	}
 }
 
@@ -75,22 +318,39 @@ func FilterByReadStatus(status *bool) Filter {
 		_     = dummy.ReadAt
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		if status == nil {
-			return query
-		}
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			if status == nil {
+				return query
+			}
 
-		if *status {
-			return query.Where("read_at is not null")
-		}
+			if *status {
+				return query.Where("read_at is not null")
+			}
 
-		return query.Where("read_at is null")
+			return query.Where("read_at is null")
+		},
+		memory: func(query *memoryQuery) {
+			if status == nil {
+				return
+			}
+
+			query.where(func(item *Item) bool {
+				return (item.ReadAt != nil) == *status
+			})
+		},
 	}
 }
 
 func WithLimit(limit, offset int) Filter {
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Offset(offset).Limit(limit)
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Offset(offset).Limit(limit)
+		},
+		memory: func(query *memoryQuery) {
+			query.offset = offset
+			query.limit = limit
+		},
 	}
 }
 
@@ -100,8 +360,15 @@ func SortedByCreatedAtDesc() Filter {
 		_     = dummy.CreatedAt
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Order("created_at desc")
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Order("created_at desc")
+		},
+		memory: func(query *memoryQuery) {
+			query.orderBy(func(a, b *Item) int {
+				return b.CreatedAt.Compare(a.CreatedAt)
+			})
+		},
 	}
 }
 
@@ -111,49 +378,254 @@ func SortedByUpdatedAtDesc() Filter {
 		_     = dummy.UpdatedAt
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Order("updated_at desc")
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Order("updated_at desc")
+		},
+		memory: func(query *memoryQuery) {
+			query.orderBy(func(a, b *Item) int {
+				return b.UpdatedAt.Compare(a.UpdatedAt)
+			})
+		},
 	}
 }
 
+// SkipSpammed skips items with the spam flag in the snapshot. Proposals without the flag are skipped as well,
+// the flag of stateless items is optional.
 func SkipSpammed() Filter {
 	var (
 		dummy Item
 		_     = dummy.Snapshot // spam flag
+		_     = dummy.Type
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Where(`snapshot->>'spam' != 'true'`)
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where(`(snapshot->>'spam' != 'true' or (type in @stateless and snapshot->>'spam' is null))`,
+				sql.Named("stateless", statelessTypes))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				spam, ok := snapshotText(item, "spam")
+
+				return ok && spam != "true" || !ok && slices.Contains(statelessTypes, item.Type)
+			})
+		},
 	}
 }
 
+// SkipCanceled skips canceled proposals, stateless items are kept.
 func SkipCanceled() Filter {
 	var (
 		dummy Item
 		_     = dummy.Snapshot // state
+		_     = dummy.Type
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Where(`snapshot->>'state' != 'canceled'`)
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where(`(type in @stateless or snapshot->>'state' != 'canceled')`, sql.Named("stateless", statelessTypes))
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				if slices.Contains(statelessTypes, item.Type) {
+					return true
+				}
+
+				state, ok := snapshotText(item, "state")
+
+				return ok && state != ProposalStateCanceled
+			})
+		},
 	}
 }
 
+// actualityCreatedPattern matches created values which are cast to bigint, others are treated as zero
+// in the same way as actualityCreated does, so the malformed snapshot doesn't break the whole feed query.
+const actualityCreatedPattern = `^[0-9]{1,18}$`
+
+var actualityCreatedRegexp = regexp.MustCompile(actualityCreatedPattern)
+
+// actualityRankExpr and actualityCreatedExpr are the keys of the actuality ordering.
+// The rank must be calculated in the same way as actualityRank does.
+var (
+	actualityRankExpr = fmt.Sprintf(
+		`coalesce(array_position(array ['%s'], snapshot->>'state'), %d)`,
+		strings.Join(actualityProposalStates, "', '"),
+		len(actualityProposalStates)+1,
+	)
+	actualityCreatedExpr = fmt.Sprintf(
+		`coalesce(case when snapshot->>'created' ~ '%s' then (snapshot->>'created')::bigint end, 0)`,
+		actualityCreatedPattern,
+	)
+)
+
+// actualityCreated returns the created key of the actuality ordering in the same way as actualityCreatedExpr does.
+func actualityCreated(item *Item) int64 {
+	created, _ := snapshotText(item, "created")
+	if !actualityCreatedRegexp.MatchString(created) {
+		return 0
+	}
+
+	// the pattern doesn't let the value overflow
+	value, _ := strconv.ParseInt(created, 10, 64)
+
+	return value
+}
+
 func SortedByActuality() Filter {
 	var (
 		dummy Item
 		_     = dummy.Snapshot // created and state
+		_     = dummy.ID
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Order(fmt.Sprintf("%s, %s desc, id", actualityRankExpr, actualityCreatedExpr))
+		},
+		memory: func(query *memoryQuery) {
+			query.orderBy(func(a, b *Item) int {
+				return compareByActuality(actualityCursor(a), actualityCursor(b))
+			})
+		},
+	}
+}
+
+// SortedByLegacyActuality is the order of the offset paged inboxapi feed kept for existing clients:
+// items without created go first in each state and created is compared as text.
+// It has no tie-breaker, so cursors must use SortedByActuality.
+func SortedByLegacyActuality() Filter {
+	var (
+		dummy Item
+		_     = dummy.Snapshot // created and state
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Order(fmt.Sprintf(
+				`array_position(array ['%s'], snapshot->>'state'), snapshot->>'created' desc`,
+				strings.Join(actualityProposalStates, "', '"),
+			))
+		},
+		memory: func(query *memoryQuery) {
+			query.orderBy(func(a, b *Item) int {
+				aState, _ := snapshotText(a, "state")
+				bState, _ := snapshotText(b, "state")
+				if result := cmp.Compare(actualityRank(aState), actualityRank(bState)); result != 0 {
+					return result
+				}
+
+				// nulls go first in the descending order
+				aCreated, aOK := snapshotText(a, "created")
+				bCreated, bOK := snapshotText(b, "created")
+				switch {
+				case aOK == bOK:
+					return strings.Compare(bCreated, aCreated)
+				case !aOK:
+					return -1
+				default:
+					return 1
+				}
+			})
+		},
+	}
+}
+
+// SortedByPrimaryKey orders items by the primary key, it's used to walk through all items by batches.
+func SortedByPrimaryKey() Filter {
+	var (
+		dummy Item
+		_     = dummy.ID
+		_     = dummy.SubscriberID
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Order("id, subscriber_id")
+		},
+		memory: func(query *memoryQuery) {
+			query.orderBy(func(a, b *Item) int {
+				return comparePrimaryKeys(a, b)
+			})
+		},
+	}
+}
+
+// FilterAfterPrimaryKey returns items placed after the given one in the SortedByPrimaryKey order.
+func FilterAfterPrimaryKey(id, subscriberID uuid.UUID) Filter {
+	var (
+		dummy Item
+		_     = dummy.ID
+		_     = dummy.SubscriberID
+	)
+
+	after := &Item{ID: id, SubscriberID: subscriberID}
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where("(id, subscriber_id) > (@after_id, @after_subscriber_id)",
+				sql.Named("after_id", id),
+				sql.Named("after_subscriber_id", subscriberID),
+			)
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return comparePrimaryKeys(item, after) > 0
+			})
+		},
+	}
+}
+
+// comparePrimaryKeys compares ids as strings in the same way as SQL does, they are stored as text.
+func comparePrimaryKeys(a, b *Item) int {
+	if result := strings.Compare(a.ID.String(), b.ID.String()); result != 0 {
+		return result
+	}
+
+	return strings.Compare(a.SubscriberID.String(), b.SubscriberID.String())
+}
+
+// FilterAfterCursor returns items placed after the cursor in the SortedByActuality order.
+func FilterAfterCursor(cursor Cursor) Filter {
+	var (
+		dummy Item
+		_     = dummy.Snapshot // created and state
+		_     = dummy.ID
 	)
 
-	return func(query *gorm.DB) *gorm.DB {
-		return query.Order(`
-				array_position(array [
-					'active',
-					'pending',
-					'succeeded',
-					'failed',
-					'defeated',
-					'canceled'
-				], snapshot->>'state'), 
-				snapshot->>'created' desc`)
+	condition := fmt.Sprintf(`(%[1]s > @rank
+		or (%[1]s = @rank and %[2]s < @created)
+		or (%[1]s = @rank and %[2]s = @created and id > @id))`,
+		actualityRankExpr,
+		actualityCreatedExpr,
+	)
+
+	return Filter{
+		db: func(query *gorm.DB) *gorm.DB {
+			return query.Where(condition,
+				sql.Named("rank", cursor.Rank),
+				sql.Named("created", cursor.Created),
+				sql.Named("id", cursor.ID),
+			)
+		},
+		memory: func(query *memoryQuery) {
+			query.where(func(item *Item) bool {
+				return compareByActuality(actualityCursor(item), cursor) > 0
+			})
+		},
+	}
+}
+
+func compareByActuality(a, b Cursor) int {
+	if a.Rank != b.Rank {
+		return cmp.Compare(a.Rank, b.Rank)
+	}
+
+	if a.Created != b.Created {
+		return cmp.Compare(b.Created, a.Created)
 	}
+
+	// ids are stored as text, so they are compared as strings
+	return strings.Compare(a.ID.String(), b.ID.String())
 }
@@ -0,0 +1,83 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// renderFilterSQL builds the postgres query of filters without the database connection.
+func renderFilterSQL(t *testing.T, filters ...Filter) string {
+	t.Helper()
+
+	conn, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	query := conn.Model(&Item{})
+	for _, f := range filters {
+		query = f.db(query)
+	}
+
+	var list []Item
+	stmt := query.Find(&list).Statement
+
+	return stmt.SQL.String()
+}
+
+// TestFiltersSQL checks lists are rendered as the list of values, `in (@ids)` renders the row constructor
+// on postgres which matches nothing for more than one value.
+func TestFiltersSQL(t *testing.T) {
+	for name, tc := range map[string]struct {
+		filter   Filter
+		expected string
+	}{
+		"ids": {
+			filter:   FilterByIDs(uuid.New(), uuid.New()),
+			expected: "id in ($1,$2)",
+		},
+		"proposal ids": {
+			filter:   FilterByProposalIDs("first", "second"),
+			expected: "proposal_id in ($1,$2)",
+		},
+		"dao ids": {
+			filter:   FilterByDaoIDs(uuid.New(), uuid.New()),
+			expected: "dao_id in ($1,$2)",
+		},
+		"actions": {
+			filter:   FilterByActions(ProposalCreated, ProposalVotingEndsSoon),
+			expected: "action in ($1,$2)",
+		},
+		"proposal states": {
+			filter:   FilterByProposalStates(ProposalStateActive, ProposalStateSucceeded),
+			expected: "snapshot->>'state' in ($1,$2)",
+		},
+		"guarded created cast": {
+			filter:   SortedByActuality(),
+			expected: "case when snapshot->>'created' ~ '^[0-9]{1,18}$' then (snapshot->>'created')::bigint end",
+		},
+		"legacy actuality order": {
+			filter:   SortedByLegacyActuality(),
+			expected: "snapshot->>'created' desc",
+		},
+		"auto-archive candidates": {
+			filter:   FilterAutoArchiveCandidates(time.Unix(100, 0)),
+			expected: "snapshot->>'state' not in ($1,$2) or (case when jsonb_typeof(snapshot->'end') = 'number'",
+		},
+		"auto-archive fields": {
+			filter:   WithAutoArchiveFields(),
+			expected: "SELECT id, subscriber_id, dao_id, type, created_at, read_at, archived_at, jsonb_build_object(",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Contains(t, renderFilterSQL(t, tc.filter), tc.expected)
+		})
+	}
+}
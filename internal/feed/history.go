@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemTransition is the change of the read or archived state of the feed item kept in its history.
+type ItemTransition string
+
+const (
+	TransitionRead       ItemTransition = "read"
+	TransitionUnread     ItemTransition = "unread"
+	TransitionArchived   ItemTransition = "archived"
+	TransitionUnarchived ItemTransition = "unarchived"
+)
+
+// TransitionSource is the entry point which changed the item.
+type TransitionSource string
+
+const (
+	// SourceGRPC changes are requested by the subscriber via the API
+	SourceGRPC TransitionSource = "grpc"
+	// SourceVoteConsumer changes are made on the vote of the subscriber, see Service.TryAutoarchive
+	SourceVoteConsumer TransitionSource = "vote_consumer"
+	// SourceWorker changes are made by background workers, e.g. the auto-archive one
+	SourceWorker TransitionSource = "worker"
+	// SourceSubscriptionConsumer changes are made when the subscriber leaves the dao, see Consumer.handlerUnsubscribed
+	SourceSubscriptionConsumer TransitionSource = "subscription_consumer"
+	// SourceFeedUpdate changes are made by the update policy when the item is updated by the core feed
+	SourceFeedUpdate TransitionSource = "feed_update"
+)
+
+// ItemHistoryEvent is the append-only record of the item transition stored in the same transaction as the change.
+type ItemHistoryEvent struct {
+	ID           int64 `gorm:"primaryKey"`
+	SubscriberID uuid.UUID
+	ItemID       uuid.UUID
+	Transition   ItemTransition
+	Source       TransitionSource
+	// ArchiveReason is set for archived items only
+	ArchiveReason ArchiveReason
+	OccurredAt    time.Time
+}
+
+func (ItemHistoryEvent) TableName() string {
+	return "item_events"
+}
+
+func newItemHistory(transition ItemTransition, source TransitionSource, items []Item, occurredAt time.Time) []ItemHistoryEvent {
+	events := make([]ItemHistoryEvent, 0, len(items))
+	for _, item := range items {
+		event := ItemHistoryEvent{
+			SubscriberID: item.SubscriberID,
+			ItemID:       item.ID,
+			Transition:   transition,
+			Source:       source,
+			OccurredAt:   occurredAt,
+		}
+		if transition == TransitionArchived {
+			event.ArchiveReason = item.ArchiveReason
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// EncodeHistoryCursor returns the opaque cursor of the history page which starts after the event.
+func EncodeHistoryCursor(event ItemHistoryEvent) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(event.ID, 10)))
+}
+
+// DecodeHistoryCursor returns the id of the event the page starts after.
+func DecodeHistoryCursor(raw string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	id, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	if id <= 0 {
+		return 0, fmt.Errorf("%w: id out of range", ErrInvalidCursor)
+	}
+
+	return id, nil
+}
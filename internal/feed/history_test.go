@@ -0,0 +1,45 @@
+package feed
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHistoryCursor(t *testing.T) {
+	for name, tc := range map[string]struct {
+		raw string
+		id  int64
+		err bool
+	}{
+		"encoded cursor": {
+			raw: EncodeHistoryCursor(ItemHistoryEvent{ID: 42}),
+			id:  42,
+		},
+		"not base64": {
+			raw: "!!!",
+			err: true,
+		},
+		"not number": {
+			raw: base64.RawURLEncoding.EncodeToString([]byte("cursor")),
+			err: true,
+		},
+		"zero id": {
+			raw: EncodeHistoryCursor(ItemHistoryEvent{}),
+			err: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actual, err := DecodeHistoryCursor(tc.raw)
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidCursor)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.id, actual)
+		})
+	}
+}
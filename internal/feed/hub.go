@@ -0,0 +1,300 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	watcherBufferSize = 32
+	hubHistorySize    = 128
+	// hubIdleTTL is how long the history of the feed is kept after its last watcher left
+	hubIdleTTL = 10 * time.Minute
+	// hubResumeWindow is how long the history of the feed which wasn't watched on this instance is kept,
+	// it lets watchers resume on this instance after the reconnect to another one
+	hubResumeWindow    = 2 * time.Minute
+	hubPruneCheckDelay = time.Minute
+	// resumeTokenVersion tells tokens built on the outbox sequence from tokens of older releases
+	resumeTokenVersion = 2
+)
+
+var ErrInvalidResumeToken = errors.New("invalid resume token")
+
+// FeedChange notifies that feed items of the subscriber were changed.
+// Changed items are identified by ids, Bulk means that the list of changed items is unknown, e.g. they are deleted.
+// Seq is the id of the latest outbox event of the change, it's the same on every instance.
+type FeedChange struct {
+	Seq          uint64
+	SubscriberID uuid.UUID
+	ItemIDs      []uuid.UUID
+	Bulk         bool
+}
+
+// Hub delivers feed changes to watchers of the subscriber feed.
+// Changes come from outbox events of all instances, see HubListener, and are ordered by the id of the event,
+// so the resume token of one instance is valid on another one. The hub keeps the short history of changes
+// of every changed feed, so the interrupted watcher could resume from the last received change on any instance.
+// Watchers which don't read changes fast enough are disconnected instead of blocking publishers.
+type Hub struct {
+	mu  sync.Mutex
+	seq uint64
+	// coveredSeq is the sequence after which the hub knows all changes of feeds it doesn't keep,
+	// it's zero until the first change is received
+	coveredSeq uint64
+	feeds      map[uuid.UUID]*hubFeed
+}
+
+type hubFeed struct {
+	// trimmedSeq is the sequence after which all changes of the feed are in the history
+	trimmedSeq uint64
+	lastSeq    uint64
+	history    []FeedChange
+	watchers   map[*Watcher]struct{}
+	watched    bool
+	idleSince  time.Time
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		feeds: make(map[uuid.UUID]*hubFeed),
+	}
+}
+
+// Publish sends the change to watchers of the subscriber feed and keeps it in the history. It never blocks.
+func (h *Hub) Publish(change FeedChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// events of older releases have no id
+	if change.Seq == 0 {
+		change.Seq = h.seq
+	}
+	if h.coveredSeq == 0 {
+		h.coveredSeq = change.Seq
+	}
+	h.seq = max(h.seq, change.Seq)
+
+	feed := h.feed(change.SubscriberID)
+	feed.lastSeq = max(feed.lastSeq, change.Seq)
+	if len(feed.watchers) == 0 {
+		feed.idleSince = time.Now()
+	}
+
+	if len(feed.history) == hubHistorySize {
+		feed.trimmedSeq = max(feed.trimmedSeq, feed.history[0].Seq)
+		feed.history = append(feed.history[:0], feed.history[1:]...)
+	}
+	feed.history = append(feed.history, change)
+
+	for w := range feed.watchers {
+		select {
+		case w.changes <- change:
+		default:
+			w.lagged = true
+			h.detach(feed, w)
+		}
+	}
+}
+
+// Reset sends the bulk change to all watchers and forgets the history, it's used when changes were lost.
+func (h *Hub) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, feed := range h.feeds {
+		if len(feed.watchers) == 0 {
+			delete(h.feeds, id)
+			continue
+		}
+
+		feed.trimmedSeq = h.seq
+		feed.history = nil
+		change := FeedChange{Seq: h.seq, SubscriberID: id, Bulk: true}
+		for w := range feed.watchers {
+			select {
+			case w.changes <- change:
+			default:
+				w.lagged = true
+				h.detach(feed, w)
+			}
+		}
+	}
+
+	h.coveredSeq = h.seq
+}
+
+// Watch subscribes to changes of the subscriber feed. If the resume token is provided the changes
+// published after it are returned to be replayed. Reset is true if these changes are lost.
+func (h *Hub) Watch(subscriberID uuid.UUID, resumeToken string) (w *Watcher, replay []FeedChange, reset bool, err error) {
+	var (
+		version int64
+		lastSeq uint64
+	)
+	if resumeToken != "" {
+		version, lastSeq, err = parseResumeToken(resumeToken)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	feed := h.feed(subscriberID)
+	feed.watched = true
+
+	w = &Watcher{
+		hub:          h,
+		subscriberID: subscriberID,
+		changes:      make(chan FeedChange, watcherBufferSize),
+		startSeq:     h.seq,
+	}
+	feed.watchers[w] = struct{}{}
+
+	if resumeToken == "" {
+		return w, nil, false, nil
+	}
+
+	// the history of the feed is complete after trimmedSeq only if the hub has received any change
+	if version != resumeTokenVersion || h.coveredSeq == 0 || lastSeq < feed.trimmedSeq {
+		return w, nil, true, nil
+	}
+
+	// the token could come from the instance which is ahead of this one, changes it hasn't received yet are delivered live
+	for _, change := range feed.history {
+		if change.Seq > lastSeq {
+			replay = append(replay, change)
+		}
+	}
+
+	return w, replay, false, nil
+}
+
+// feed returns the state of the subscriber feed, it must be called under the lock.
+func (h *Hub) feed(subscriberID uuid.UUID) *hubFeed {
+	feed, ok := h.feeds[subscriberID]
+	if !ok {
+		// there were no changes of the feed since coveredSeq, otherwise it would be kept
+		feed = &hubFeed{
+			trimmedSeq: h.coveredSeq,
+			lastSeq:    h.coveredSeq,
+			watchers:   make(map[*Watcher]struct{}),
+			idleSince:  time.Now(),
+		}
+		h.feeds[subscriberID] = feed
+	}
+
+	return feed
+}
+
+// ResumeToken returns the token to resume watching after the given change.
+func (h *Hub) ResumeToken(seq uint64) string {
+	return fmt.Sprintf("%d.%d", resumeTokenVersion, seq)
+}
+
+// Start removes the history of feeds without watchers periodically.
+func (h *Hub) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(hubPruneCheckDelay):
+			h.prune(time.Now())
+		}
+	}
+}
+
+func (h *Hub) prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, feed := range h.feeds {
+		ttl := hubResumeWindow
+		if feed.watched {
+			ttl = hubIdleTTL
+		}
+
+		if len(feed.watchers) == 0 && now.Sub(feed.idleSince) > ttl {
+			// tokens before the last change of the removed feed can't be resumed anymore
+			h.coveredSeq = max(h.coveredSeq, feed.lastSeq)
+			delete(h.feeds, id)
+		}
+	}
+}
+
+func (h *Hub) detach(feed *hubFeed, w *Watcher) {
+	if _, ok := feed.watchers[w]; !ok {
+		return
+	}
+
+	delete(feed.watchers, w)
+	close(w.changes)
+
+	if len(feed.watchers) == 0 {
+		feed.idleSince = time.Now()
+	}
+}
+
+func parseResumeToken(token string) (int64, uint64, error) {
+	rawVersion, rawSeq, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, 0, ErrInvalidResumeToken
+	}
+
+	version, err := strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidResumeToken, err)
+	}
+
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidResumeToken, err)
+	}
+
+	return version, seq, nil
+}
+
+// Watcher receives changes of the subscriber feed until it's closed.
+type Watcher struct {
+	hub          *Hub
+	subscriberID uuid.UUID
+	changes      chan FeedChange
+	startSeq     uint64
+	// lagged is set by the hub when the watcher is detached due to the full buffer
+	lagged bool
+}
+
+// StartSeq returns the sequence of the latest change published before the watcher was created.
+func (w *Watcher) StartSeq() uint64 {
+	return w.startSeq
+}
+
+// Changes returns the channel of changes, it's closed when the watcher is detached.
+func (w *Watcher) Changes() <-chan FeedChange {
+	return w.changes
+}
+
+// Lagged reports if the watcher was detached because it didn't read changes in time.
+// It must be called after the changes channel is closed.
+func (w *Watcher) Lagged() bool {
+	w.hub.mu.Lock()
+	defer w.hub.mu.Unlock()
+
+	return w.lagged
+}
+
+func (w *Watcher) Close() {
+	w.hub.mu.Lock()
+	defer w.hub.mu.Unlock()
+
+	if feed, ok := w.hub.feeds[w.subscriberID]; ok {
+		w.hub.detach(feed, w)
+	}
+}
@@ -0,0 +1,133 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// hubListenerBufferSize is the number of received events waiting for the hub, NATS drops events above it
+	hubListenerBufferSize = 4096
+	// hubListenerCheckDelay is the interval of checks for lost events when no events are received
+	hubListenerCheckDelay = 5 * time.Second
+	// hubListenerBatchSize limits events merged into changes at once
+	hubListenerBatchSize = 512
+	// maxChangeItems is the number of changed items above which the change is sent as the bulk one
+	maxChangeItems = 100
+)
+
+// HubListener drives the hub by feed item events which the outbox relay of any instance publishes, so watchers
+// get changes made on every instance. Every instance receives all events, the subscription has no queue group.
+// Events received at once are merged into the single change per subscriber, so bulk updates don't flood watchers.
+type HubListener struct {
+	conn *nats.Conn
+	hub  *Hub
+}
+
+func NewHubListener(conn *nats.Conn, hub *Hub) *HubListener {
+	return &HubListener{
+		conn: conn,
+		hub:  hub,
+	}
+}
+
+func (l *HubListener) Start(ctx context.Context) error {
+	messages := make(chan *nats.Msg, hubListenerBufferSize)
+	sub, err := l.conn.ChanSubscribe(subjectItemEvents, messages)
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", subjectItemEvents, err)
+	}
+	defer func() {
+		if err := sub.Unsubscribe(); err != nil {
+			log.Warn().Err(err).Msg("unsubscribe from feed item events")
+		}
+	}()
+
+	check := time.NewTicker(hubListenerCheckDelay)
+	defer check.Stop()
+
+	var (
+		dropped    int
+		reconnects = l.conn.Stats().Reconnects
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-check.C:
+		case msg := <-messages:
+			batch := append(make([]*nats.Msg, 0, hubListenerBatchSize), msg)
+			for len(batch) < hubListenerBatchSize && len(messages) > 0 {
+				batch = append(batch, <-messages)
+			}
+
+			for _, change := range feedChanges(batch) {
+				l.hub.Publish(change)
+			}
+		}
+
+		// events are lost if the buffer is full or while the connection is down, watchers can't tell
+		// which changes they missed, so all of them are refreshed
+		current, err := sub.Dropped()
+		if err != nil {
+			return fmt.Errorf("check dropped events: %w", err)
+		}
+		if current > dropped || l.conn.Stats().Reconnects > reconnects {
+			log.Warn().Int("dropped", current-dropped).Msg("feed item events are lost, watchers are reset")
+			dropped, reconnects = current, l.conn.Stats().Reconnects
+			l.hub.Reset()
+		}
+	}
+}
+
+// feedChanges merges item events into changes of subscriber feeds in the order the feeds were changed.
+// Deleted items can't be found anymore, so their changes are bulk ones.
+func feedChanges(messages []*nats.Msg) []FeedChange {
+	var (
+		order   []uuid.UUID
+		changes = make(map[uuid.UUID]*FeedChange)
+	)
+	for _, msg := range messages {
+		var event ItemEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil || event.SubscriberID == uuid.Nil {
+			log.Warn().Err(err).Str("subject", msg.Subject).Msg("skip malformed feed item event")
+			continue
+		}
+
+		change, ok := changes[event.SubscriberID]
+		if !ok {
+			change = &FeedChange{SubscriberID: event.SubscriberID}
+			changes[event.SubscriberID] = change
+			order = append(order, event.SubscriberID)
+		}
+
+		// events of older releases have no id, the hub delivers them with the latest known sequence
+		change.Seq = max(change.Seq, uint64(max(event.EventID, 0)))
+		switch {
+		case change.Bulk, slices.Contains(change.ItemIDs, event.ID):
+		case msg.Subject == SubjectItemDeleted, len(change.ItemIDs) == maxChangeItems:
+			change.Bulk = true
+		default:
+			change.ItemIDs = append(change.ItemIDs, event.ID)
+		}
+	}
+
+	result := make([]FeedChange, 0, len(order))
+	for _, subscriberID := range order {
+		change := changes[subscriberID]
+		if change.Bulk {
+			change.ItemIDs = nil
+		}
+
+		result = append(result, *change)
+	}
+
+	return result
+}
@@ -0,0 +1,58 @@
+package feed
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func itemEventMsg(t *testing.T, subject string, eventID int64, subscriberID, itemID uuid.UUID) *nats.Msg {
+	data, err := json.Marshal(ItemEvent{EventID: eventID, ID: itemID, SubscriberID: subscriberID})
+	require.NoError(t, err)
+
+	return &nats.Msg{Subject: subject, Data: data}
+}
+
+func TestFeedChanges(t *testing.T) {
+	subscriber, another, deleted := uuid.New(), uuid.New(), uuid.New()
+	item, other := uuid.New(), uuid.New()
+
+	messages := []*nats.Msg{
+		itemEventMsg(t, SubjectItemUpdated, 3, another, other),
+		itemEventMsg(t, SubjectItemCreated, 1, subscriber, item),
+		{Subject: SubjectItemUpdated, Data: []byte("{")},
+		itemEventMsg(t, SubjectItemUpdated, 5, subscriber, item),
+		itemEventMsg(t, SubjectItemRead, 4, subscriber, other),
+		itemEventMsg(t, SubjectItemUpdated, 6, deleted, item),
+		itemEventMsg(t, SubjectItemDeleted, 7, deleted, other),
+	}
+
+	assert.Equal(t, []FeedChange{
+		{Seq: 3, SubscriberID: another, ItemIDs: []uuid.UUID{other}},
+		{Seq: 5, SubscriberID: subscriber, ItemIDs: []uuid.UUID{item, other}},
+		{Seq: 7, SubscriberID: deleted, Bulk: true},
+	}, feedChanges(messages))
+}
+
+func TestFeedChanges_ManyItems(t *testing.T) {
+	subscriber := uuid.New()
+
+	messages := make([]*nats.Msg, 0, maxChangeItems+1)
+	for i := range maxChangeItems {
+		messages = append(messages, itemEventMsg(t, SubjectItemUpdated, int64(i+1), subscriber, uuid.New()))
+	}
+
+	changes := feedChanges(messages)
+	require.Len(t, changes, 1)
+	assert.False(t, changes[0].Bulk)
+	assert.Len(t, changes[0].ItemIDs, maxChangeItems)
+
+	messages = append(messages, itemEventMsg(t, SubjectItemUpdated, maxChangeItems+1, subscriber, uuid.New()))
+	assert.Equal(t, []FeedChange{
+		{Seq: maxChangeItems + 1, SubscriberID: subscriber, Bulk: true},
+	}, feedChanges(messages))
+}
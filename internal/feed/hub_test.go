@@ -0,0 +1,238 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, w *Watcher) FeedChange {
+	select {
+	case change, ok := <-w.Changes():
+		require.True(t, ok, "watcher is detached")
+		return change
+	default:
+		require.FailNow(t, "no changes")
+		return FeedChange{}
+	}
+}
+
+func TestHub_Publish(t *testing.T) {
+	hub := NewHub()
+	subscriber, another := uuid.New(), uuid.New()
+
+	w, replay, reset, err := hub.Watch(subscriber, "")
+	require.NoError(t, err)
+	defer w.Close()
+	assert.Empty(t, replay)
+	assert.False(t, reset)
+
+	itemID := uuid.New()
+	hub.Publish(FeedChange{Seq: 10, SubscriberID: another, Bulk: true})
+	hub.Publish(FeedChange{Seq: 12, SubscriberID: subscriber, ItemIDs: []uuid.UUID{itemID}})
+
+	change := receive(t, w)
+	assert.Equal(t, []uuid.UUID{itemID}, change.ItemIDs)
+	assert.Equal(t, uint64(12), change.Seq, "changes of not watched feeds are skipped")
+	assert.Empty(t, w.Changes())
+
+	hub.Publish(FeedChange{SubscriberID: subscriber, Bulk: true})
+	assert.Equal(t, uint64(12), receive(t, w).Seq, "changes without the event id get the latest sequence")
+}
+
+func TestHub_Resume(t *testing.T) {
+	subscriber := uuid.New()
+
+	for name, tc := range map[string]struct {
+		published int
+		token     string
+		replay    []uint64
+		reset     bool
+	}{
+		"replay changes after the token": {
+			published: 3,
+			token:     "2.1",
+			replay:    []uint64{2, 3},
+		},
+		"nothing to replay": {
+			published: 2,
+			token:     "2.2",
+		},
+		"history is trimmed": {
+			published: hubHistorySize + 2,
+			token:     "2.1",
+			reset:     true,
+		},
+		"token of the older release": {
+			published: 1,
+			token:     "1.1",
+			reset:     true,
+		},
+		"token of the instance which is ahead": {
+			published: 1,
+			token:     "2.5",
+		},
+		"no changes received yet": {
+			token: "2.1",
+			reset: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			hub := NewHub()
+			w, _, _, err := hub.Watch(subscriber, "")
+			require.NoError(t, err)
+
+			for i := 1; i <= tc.published; i++ {
+				hub.Publish(FeedChange{Seq: uint64(i), SubscriberID: subscriber, Bulk: true})
+			}
+			w.Close()
+
+			resumed, replay, reset, err := hub.Watch(subscriber, tc.token)
+			require.NoError(t, err)
+			defer resumed.Close()
+
+			var seqs []uint64
+			for _, change := range replay {
+				seqs = append(seqs, change.Seq)
+			}
+			assert.Equal(t, tc.replay, seqs)
+			assert.Equal(t, tc.reset, reset)
+		})
+	}
+}
+
+func TestHub_ResumeOnAnotherInstance(t *testing.T) {
+	subscriber, another := uuid.New(), uuid.New()
+	first, second := NewHub(), NewHub()
+
+	w, _, _, err := first.Watch(subscriber, "")
+	require.NoError(t, err)
+
+	// both instances receive the same events, the second one doesn't have watchers
+	for _, change := range []FeedChange{
+		{Seq: 3, SubscriberID: another, Bulk: true},
+		{Seq: 5, SubscriberID: subscriber, Bulk: true},
+		{Seq: 8, SubscriberID: subscriber, Bulk: true},
+	} {
+		first.Publish(change)
+		second.Publish(change)
+	}
+
+	token := first.ResumeToken(receive(t, w).Seq)
+	w.Close()
+
+	resumed, replay, reset, err := second.Watch(subscriber, token)
+	require.NoError(t, err)
+	defer resumed.Close()
+	assert.False(t, reset)
+	require.Len(t, replay, 1)
+	assert.Equal(t, uint64(8), replay[0].Seq)
+
+	// the feed without changes since the first received event has nothing to replay
+	_, replay, reset, err = second.Watch(uuid.New(), first.ResumeToken(3))
+	require.NoError(t, err)
+	assert.Empty(t, replay)
+	assert.False(t, reset)
+
+	// changes before the first received event are unknown
+	_, _, reset, err = second.Watch(uuid.New(), first.ResumeToken(2))
+	require.NoError(t, err)
+	assert.True(t, reset)
+}
+
+func TestHub_Reset(t *testing.T) {
+	hub := NewHub()
+	subscriber, idle := uuid.New(), uuid.New()
+
+	w, _, _, err := hub.Watch(subscriber, "")
+	require.NoError(t, err)
+	defer w.Close()
+
+	hub.Publish(FeedChange{Seq: 4, SubscriberID: subscriber, ItemIDs: []uuid.UUID{uuid.New()}})
+	hub.Publish(FeedChange{Seq: 6, SubscriberID: idle, Bulk: true})
+	receive(t, w)
+
+	hub.Reset()
+	change := receive(t, w)
+	assert.True(t, change.Bulk)
+	assert.Equal(t, uint64(6), change.Seq)
+	assert.NotContains(t, hub.feeds, idle, "feeds without watchers are forgotten")
+
+	_, _, reset, err := hub.Watch(idle, hub.ResumeToken(5))
+	require.NoError(t, err)
+	assert.True(t, reset, "changes before the reset are lost")
+
+	_, replay, reset, err := hub.Watch(idle, hub.ResumeToken(6))
+	require.NoError(t, err)
+	assert.Empty(t, replay)
+	assert.False(t, reset)
+}
+
+func TestHub_InvalidResumeToken(t *testing.T) {
+	for _, token := range []string{"token", "1.", ".1", "a.1", "1.-1"} {
+		_, _, _, err := NewHub().Watch(uuid.New(), token)
+		assert.ErrorIs(t, err, ErrInvalidResumeToken, token)
+	}
+}
+
+func TestHub_DetachLaggedWatcher(t *testing.T) {
+	hub := NewHub()
+	subscriber := uuid.New()
+
+	slow, _, _, err := hub.Watch(subscriber, "")
+	require.NoError(t, err)
+	fast, _, _, err := hub.Watch(subscriber, "")
+	require.NoError(t, err)
+	defer fast.Close()
+
+	for i := 1; i <= watcherBufferSize+1; i++ {
+		hub.Publish(FeedChange{Seq: uint64(i), SubscriberID: subscriber, Bulk: true})
+		receive(t, fast)
+	}
+
+	var received int
+	for range slow.Changes() {
+		received++
+	}
+	assert.Equal(t, watcherBufferSize, received)
+	assert.True(t, slow.Lagged())
+	assert.False(t, fast.Lagged())
+
+	// closing of the detached watcher is safe
+	slow.Close()
+}
+
+func TestHub_Prune(t *testing.T) {
+	hub := NewHub()
+	watched, idle, changed := uuid.New(), uuid.New(), uuid.New()
+
+	w, _, _, err := hub.Watch(watched, "")
+	require.NoError(t, err)
+	defer w.Close()
+
+	closed, _, _, err := hub.Watch(idle, "")
+	require.NoError(t, err)
+	closed.Close()
+
+	hub.Publish(FeedChange{Seq: 1, SubscriberID: changed, Bulk: true})
+	hub.Publish(FeedChange{Seq: 2, SubscriberID: watched, Bulk: true})
+
+	hub.prune(time.Now())
+	assert.Len(t, hub.feeds, 3, "recent feeds are kept")
+
+	hub.prune(time.Now().Add(hubResumeWindow + time.Second))
+	assert.Len(t, hub.feeds, 2, "feeds which weren't watched are kept for the resume window")
+	assert.NotContains(t, hub.feeds, changed)
+
+	resumed, _, reset, err := hub.Watch(changed, hub.ResumeToken(0))
+	require.NoError(t, err)
+	resumed.Close()
+	assert.True(t, reset, "the history of the pruned feed is lost")
+
+	hub.prune(time.Now().Add(hubIdleTTL + time.Second))
+	assert.Len(t, hub.feeds, 1)
+	assert.Contains(t, hub.feeds, watched)
+}
@@ -0,0 +1,1109 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemoryStore is the in-memory FeedStore. It follows the semantics of Repo including
+// filters, so it could be used instead of the database in tests.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	items         []*Item
+	index         map[itemKey]int
+	settings      map[uuid.UUID]Settings
+	counters      map[uuid.UUID]Counters
+	outbox        []OutboxEvent
+	history       []ItemHistoryEvent
+	backfills     map[backfillKey]*BackfillJob
+	locks         map[int64]bool
+	jobRuns       map[string]time.Time
+	lastOutboxID  int64
+	lastHistoryID int64
+	policy        UpdatePolicy
+}
+
+func NewMemoryStore(policy UpdatePolicy) *MemoryStore {
+	return &MemoryStore{
+		index:     make(map[itemKey]int),
+		settings:  make(map[uuid.UUID]Settings),
+		counters:  make(map[uuid.UUID]Counters),
+		backfills: make(map[backfillKey]*BackfillJob),
+		locks:     make(map[int64]bool),
+		jobRuns:   make(map[string]time.Time),
+		policy:    policy,
+	}
+}
+
+func (m *MemoryStore) CreateOrUpdate(_ context.Context, item *Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delta := make(countersDelta)
+	subject := SubjectItemUpdated
+	stored, created, outcome := m.upsert(*item, time.Now(), delta)
+	if created {
+		subject = SubjectItemCreated
+	}
+
+	*item = stored
+	if outcome == outcomeFrozen {
+		return nil
+	}
+
+	m.applyCountersDelta(delta)
+
+	if err := m.storeEvents(subject, []Item{stored}); err != nil {
+		return err
+	}
+
+	for _, outcomeSubject := range outcome.subjects() {
+		if err := m.storeEvents(outcomeSubject, []Item{stored}); err != nil {
+			return err
+		}
+	}
+
+	for _, transition := range outcome.transitions() {
+		m.storeHistory(transition, SourceFeedUpdate, []Item{stored}, stored.UpdatedAt)
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) BulkCreateOrUpdate(_ context.Context, items []Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var created, updated []Item
+	byOutcome := make(map[string][]Item)
+	byTransition := make(map[ItemTransition][]Item)
+	delta := make(countersDelta)
+	for _, item := range uniqueItems(items) {
+		updatedAt := item.UpdatedAt
+		if updatedAt.IsZero() {
+			updatedAt = time.Now()
+		}
+
+		stored, ok, outcome := m.upsert(item, updatedAt, delta)
+		if outcome == outcomeFrozen {
+			continue
+		}
+
+		if ok {
+			created = append(created, stored)
+		} else {
+			updated = append(updated, stored)
+		}
+
+		for _, subject := range outcome.subjects() {
+			byOutcome[subject] = append(byOutcome[subject], stored)
+		}
+		for _, transition := range outcome.transitions() {
+			byTransition[transition] = append(byTransition[transition], stored)
+		}
+	}
+
+	m.applyCountersDelta(delta)
+
+	if err := m.storeEvents(SubjectItemCreated, created); err != nil {
+		return err
+	}
+
+	if err := m.storeEvents(SubjectItemUpdated, updated); err != nil {
+		return err
+	}
+
+	for _, subject := range outcomeSubjects {
+		if err := m.storeEvents(subject, byOutcome[subject]); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for _, transition := range outcomeTransitions {
+		m.storeHistory(transition, SourceFeedUpdate, byTransition[transition], now)
+	}
+
+	return nil
+}
+
+// upsert creates or updates the item, takes into account the change in the delta
+// and returns the stored item, if it was created and the outcome of the update.
+func (m *MemoryStore) upsert(item Item, updatedAt time.Time, delta countersDelta) (Item, bool, updateOutcome) {
+	now := time.Now()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+
+	key := item.key()
+	if idx, ok := m.index[key]; ok {
+		stored := m.items[idx]
+		before := *stored
+		outcome := m.policy.apply(&before, &item, now)
+		if outcome == outcomeFrozen {
+			return before, false, outcome
+		}
+
+		stored.ReadAt = item.ReadAt
+		stored.ResurfacedAt = item.ResurfacedAt
+		stored.ResurfacedReason = item.ResurfacedReason
+		stored.ArchivedAt = item.ArchivedAt
+		stored.ArchiveReason = item.ArchiveReason
+		stored.UnarchivedAt = item.UnarchivedAt
+		stored.UnarchivedReason = item.UnarchivedReason
+		stored.SnoozedAt = item.SnoozedAt
+		stored.SnoozedUntil = item.SnoozedUntil
+		stored.SnoozedUntilAction = item.SnoozedUntilAction
+		stored.DiscussionID = item.DiscussionID
+		stored.Snapshot = item.Snapshot
+		stored.Timeline = item.Timeline
+		stored.Action = item.Action
+		stored.CreatedAt = item.CreatedAt
+		stored.UpdatedAt = updatedAt
+		delta.change(&before, stored)
+
+		return *stored, false, outcome
+	}
+
+	if item.UpdatedAt.IsZero() {
+		item.UpdatedAt = now
+	}
+
+	m.index[key] = len(m.items)
+	m.items = append(m.items, &item)
+	delta.change(nil, &item)
+
+	return item, true, outcomeUpdated
+}
+
+func (m *MemoryStore) FindSubscribersByProposalID(_ context.Context, proposalID string) ([]uuid.UUID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var subscribers []uuid.UUID
+	for _, item := range m.items {
+		if item.DeletedAt.Valid || item.FrozenAt != nil || item.ProposalID != proposalID || slices.Contains(subscribers, item.SubscriberID) {
+			continue
+		}
+
+		subscribers = append(subscribers, item.SubscriberID)
+	}
+
+	return subscribers, nil
+}
+
+func (m *MemoryStore) FindSubscribersByDiscussionID(_ context.Context, discussionID string) ([]uuid.UUID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var subscribers []uuid.UUID
+	for _, item := range m.items {
+		if item.DeletedAt.Valid || item.FrozenAt != nil || item.Type != Discussion || item.DiscussionID != discussionID ||
+			slices.Contains(subscribers, item.SubscriberID) {
+			continue
+		}
+
+		subscribers = append(subscribers, item.SubscriberID)
+	}
+
+	return subscribers, nil
+}
+
+func (m *MemoryStore) FindSubscribersByDaoItem(_ context.Context, daoID uuid.UUID) ([]uuid.UUID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var subscribers []uuid.UUID
+	for _, item := range m.items {
+		if item.DeletedAt.Valid || item.FrozenAt != nil || !item.DAO() || item.DaoID != daoID ||
+			slices.Contains(subscribers, item.SubscriberID) {
+			continue
+		}
+
+		subscribers = append(subscribers, item.SubscriberID)
+	}
+
+	return subscribers, nil
+}
+
+func (m *MemoryStore) MarkAsReadByID(_ context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error {
+	return m.mark(SubjectItemRead, TransitionRead, source, subscriberID, byIDs(id), func(item *Item, now time.Time) {
+		item.ReadAt = &now
+	})
+}
+
+func (m *MemoryStore) MarkAsUnreadByID(_ context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error {
+	return m.mark(SubjectItemUnread, TransitionUnread, source, subscriberID, byIDs(id), func(item *Item, _ time.Time) {
+		item.ReadAt = nil
+	})
+}
+
+func (m *MemoryStore) MarkAsReadByTime(_ context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error {
+	return m.mark(SubjectItemRead, TransitionRead, source, subscriberID, func(item *Item) bool {
+		return !item.UpdatedAt.After(t) && item.ReadAt == nil
+	}, func(item *Item, now time.Time) {
+		item.ReadAt = &now
+	})
+}
+
+func (m *MemoryStore) MarkAsUnreadByTime(_ context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error {
+	return m.mark(SubjectItemUnread, TransitionUnread, source, subscriberID, func(item *Item) bool {
+		return !item.UpdatedAt.Before(t) && item.ReadAt != nil
+	}, func(item *Item, _ time.Time) {
+		item.ReadAt = nil
+	})
+}
+
+func (m *MemoryStore) MarkAsArchivedByID(_ context.Context, subscriberID uuid.UUID, source TransitionSource, reason ArchiveReason, id ...uuid.UUID) error {
+	return m.mark(SubjectItemArchived, TransitionArchived, source, subscriberID, byIDs(id), func(item *Item, now time.Time) {
+		item.ArchivedAt = &now
+		item.ArchiveReason = reason
+		item.UnarchivedAt = nil
+		item.UnarchivedReason = ""
+		unsnooze(item)
+	})
+}
+
+func (m *MemoryStore) MarkAsUnarchivedByID(_ context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error {
+	return m.mark(SubjectItemUnarchived, TransitionUnarchived, source, subscriberID, byIDs(id), func(item *Item, now time.Time) {
+		item.ArchivedAt = nil
+		item.ArchiveReason = ""
+		item.UnarchivedAt = &now
+		item.UnarchivedReason = UnarchivedManually
+	})
+}
+
+func (m *MemoryStore) MarkAsVoted(_ context.Context, subscriberID uuid.UUID, proposalID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, item := range m.items {
+		if item.SubscriberID == subscriberID && item.ProposalID == proposalID && item.VotedAt == nil {
+			item.VotedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Unsubscribe(_ context.Context, subscriberID uuid.UUID, source TransitionSource, daoID uuid.UUID, mode UnsubscribeMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	byDao := func(item *Item) bool {
+		return item.DaoID == daoID
+	}
+
+	if job, ok := m.backfills[backfillKey{subscriberID: subscriberID, daoID: daoID}]; ok && (job.Status == BackfillPending || job.Status == BackfillRunning) {
+		job.Status = BackfillCanceled
+		job.UpdatedAt = now
+		job.FinishedAt = &now
+	}
+
+	switch mode {
+	case UnsubscribePurge:
+		return m.delete(subscriberID, byDao, now)
+	case UnsubscribeArchive:
+		archived, err := m.updateLocked(SubjectItemArchived, subscriberID, func(item *Item) bool {
+			return byDao(item) && item.ArchivedAt == nil
+		}, func(item *Item, now time.Time) {
+			item.ArchivedAt = &now
+			item.ArchiveReason = ArchivedUnsubscribed
+			item.UnarchivedAt = nil
+			item.UnarchivedReason = ""
+			unsnooze(item)
+		}, now)
+		if err != nil {
+			return err
+		}
+
+		m.storeHistory(TransitionArchived, source, archived, now)
+	case UnsubscribeFreeze:
+	default:
+		return fmt.Errorf("unknown unsubscribe mode: %s", mode)
+	}
+
+	for _, item := range m.items {
+		if item.SubscriberID == subscriberID && byDao(item) && item.FrozenAt == nil {
+			item.FrozenAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Unfreeze(_ context.Context, subscriberID uuid.UUID, daoID ...uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, item := range m.items {
+		if item.SubscriberID == subscriberID && slices.Contains(daoID, item.DaoID) {
+			item.FrozenAt = nil
+		}
+	}
+
+	return nil
+}
+
+// delete removes matched items including soft deleted ones and stores events about them, the caller must hold the lock.
+func (m *MemoryStore) delete(subscriberID uuid.UUID, match func(item *Item) bool, now time.Time) error {
+	var deleted []Item
+	delta := make(countersDelta)
+	kept := m.items[:0]
+	for _, item := range m.items {
+		if item.SubscriberID != subscriberID || !match(item) {
+			kept = append(kept, item)
+			continue
+		}
+
+		if !item.DeletedAt.Valid {
+			delta.change(item, nil)
+		}
+		deleted = append(deleted, *item)
+	}
+
+	m.items = kept
+	m.index = make(map[itemKey]int, len(kept))
+	for idx, item := range kept {
+		m.index[item.key()] = idx
+	}
+
+	m.applyCountersDelta(delta)
+
+	return m.storeEvents(SubjectItemDeleted, deleted)
+}
+
+func (m *MemoryStore) MarkAsArchivedByTime(_ context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error {
+	return m.mark(SubjectItemArchived, TransitionArchived, source, subscriberID, func(item *Item) bool {
+		return !item.CreatedAt.After(t) && item.ArchivedAt == nil
+	}, func(item *Item, now time.Time) {
+		item.ArchivedAt = &now
+		item.ArchiveReason = ArchivedBulkByTime
+		unsnooze(item)
+	})
+}
+
+// update changes matched items and stores events about them. Like gorm, it sets UpdatedAt of changed items.
+func (m *MemoryStore) update(subject string, subscriberID uuid.UUID, match func(item *Item) bool, fn func(item *Item, now time.Time)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := m.updateLocked(subject, subscriberID, match, fn, time.Now())
+
+	return err
+}
+
+// mark does the same as update and appends the transition of changed items to their history.
+func (m *MemoryStore) mark(subject string, transition ItemTransition, source TransitionSource, subscriberID uuid.UUID, match func(item *Item) bool, fn func(item *Item, now time.Time)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	changed, err := m.updateLocked(subject, subscriberID, match, fn, now)
+	if err != nil {
+		return err
+	}
+
+	m.storeHistory(transition, source, changed, now)
+
+	return nil
+}
+
+// updateLocked does the same as update and returns changed items, the caller must hold the lock.
+func (m *MemoryStore) updateLocked(subject string, subscriberID uuid.UUID, match func(item *Item) bool, fn func(item *Item, now time.Time), now time.Time) ([]Item, error) {
+	var changed []Item
+	delta := make(countersDelta)
+	for _, item := range m.items {
+		if item.DeletedAt.Valid || item.SubscriberID != subscriberID || !match(item) {
+			continue
+		}
+
+		before := *item
+		fn(item, now)
+		item.UpdatedAt = now
+		delta.change(&before, item)
+		changed = append(changed, *item)
+	}
+
+	m.applyCountersDelta(delta)
+
+	if err := m.storeEvents(subject, changed); err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+func (m *MemoryStore) applyCountersDelta(delta countersDelta) {
+	now := time.Now()
+	for _, d := range delta.list() {
+		c := m.counters[d.SubscriberID]
+		c.SubscriberID = d.SubscriberID
+		c.UpdatedAt = now
+		c.TotalCount += d.TotalCount
+		c.UnreadCount += d.UnreadCount
+		c.ArchivedCount += d.ArchivedCount
+		m.counters[d.SubscriberID] = c
+	}
+}
+
+func (m *MemoryStore) GetCounters(_ context.Context, subscriberID uuid.UUID) (Counters, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c, ok := m.counters[subscriberID]
+	if !ok {
+		return Counters{SubscriberID: subscriberID}, nil
+	}
+
+	return c, nil
+}
+
+func (m *MemoryStore) ReconcileCounters(_ context.Context, after uuid.UUID, limit int) ([]uuid.UUID, []Counters, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	actual := make(countersDelta)
+	for _, item := range m.items {
+		if strings.Compare(item.SubscriberID.String(), after.String()) <= 0 {
+			continue
+		}
+
+		if _, ok := actual[item.SubscriberID]; !ok {
+			actual[item.SubscriberID] = &Counters{SubscriberID: item.SubscriberID}
+		}
+
+		actual.change(nil, item)
+	}
+
+	for id := range m.counters {
+		if _, ok := actual[id]; !ok && strings.Compare(id.String(), after.String()) > 0 {
+			actual[id] = &Counters{SubscriberID: id}
+		}
+	}
+
+	subscribers := make([]uuid.UUID, 0, len(actual))
+	for id := range actual {
+		subscribers = append(subscribers, id)
+	}
+
+	slices.SortFunc(subscribers, func(a, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	subscribers = window(subscribers, 0, limit)
+
+	var fixed []Counters
+	for _, id := range subscribers {
+		expected := *actual[id]
+		expected.UpdatedAt = time.Now()
+
+		// like the database, missing counters are created empty
+		stored, ok := m.counters[id]
+		if !ok {
+			stored = Counters{SubscriberID: id, UpdatedAt: expected.UpdatedAt}
+			m.counters[id] = stored
+		}
+
+		if sameCounts(stored, expected) {
+			continue
+		}
+
+		m.counters[id] = expected
+		fixed = append(fixed, expected)
+	}
+
+	return subscribers, fixed, nil
+}
+
+func (m *MemoryStore) Snooze(_ context.Context, subscriberID uuid.UUID, snooze Snooze, id ...uuid.UUID) error {
+	return m.update(SubjectItemSnoozed, subscriberID, func(item *Item) bool {
+		return slices.Contains(id, item.ID) && item.ArchivedAt == nil
+	}, func(item *Item, now time.Time) {
+		item.SnoozedAt = &now
+		item.SnoozedUntil = snooze.Until
+		item.SnoozedUntilAction = snooze.UntilAction
+	})
+}
+
+func (m *MemoryStore) Unsnooze(_ context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+	return m.update(SubjectItemUnsnoozed, subscriberID, func(item *Item) bool {
+		return slices.Contains(id, item.ID) && item.SnoozedAt != nil
+	}, func(item *Item, _ time.Time) {
+		unsnooze(item)
+	})
+}
+
+func (m *MemoryStore) WakeSnoozed(_ context.Context, now time.Time) ([]uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var woken []Item
+	delta := make(countersDelta)
+	for _, item := range m.items {
+		if item.DeletedAt.Valid || item.ArchivedAt != nil || item.SnoozedAt == nil || item.SnoozedUntil == nil || item.SnoozedUntil.After(now) {
+			continue
+		}
+
+		before := *item
+		wake(item)
+		item.UpdatedAt = now
+		delta.change(&before, item)
+		woken = append(woken, *item)
+	}
+
+	m.applyCountersDelta(delta)
+
+	if err := m.storeEvents(SubjectItemUnsnoozed, woken); err != nil {
+		return nil, err
+	}
+
+	m.storeHistory(TransitionUnread, SourceWorker, woken, now)
+
+	return itemSubscribers(woken), nil
+}
+
+func byIDs(ids []uuid.UUID) func(item *Item) bool {
+	return func(item *Item) bool {
+		return slices.Contains(ids, item.ID)
+	}
+}
+
+func (m *MemoryStore) CountByFilters(_ context.Context, filters []Filter) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	query := newMemoryQuery(filters)
+
+	var count int64
+	for _, item := range m.items {
+		if query.match(item) {
+			count++
+		}
+	}
+
+	// the database applies offset and limit to the single row with the counter
+	counters := window([]int64{count}, query.offset, query.limit)
+	if len(counters) == 0 {
+		return 0, nil
+	}
+
+	return counters[0], nil
+}
+
+func (m *MemoryStore) CountByDao(_ context.Context, filters []Filter) ([]DaoCounters, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	query := newMemoryQuery(filters)
+
+	counters := make(map[uuid.UUID]*DaoCounters)
+	for _, item := range m.items {
+		if !query.match(item) {
+			continue
+		}
+
+		dc, ok := counters[item.DaoID]
+		if !ok {
+			dc = &DaoCounters{DaoID: item.DaoID}
+			counters[item.DaoID] = dc
+		}
+
+		dc.TotalCount++
+		if item.ReadAt == nil {
+			dc.UnreadCount++
+		}
+	}
+
+	list := make([]DaoCounters, 0, len(counters))
+	for _, dc := range counters {
+		list = append(list, *dc)
+	}
+
+	slices.SortFunc(list, func(a, b DaoCounters) int {
+		return strings.Compare(a.DaoID.String(), b.DaoID.String())
+	})
+
+	return list, nil
+}
+
+func (m *MemoryStore) FindByFilters(_ context.Context, filters []Filter) ([]Item, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	query := newMemoryQuery(filters)
+
+	var found []*Item
+	for _, item := range m.items {
+		if query.match(item) {
+			found = append(found, item)
+		}
+	}
+
+	slices.SortStableFunc(found, query.compare)
+
+	found = window(found, query.offset, query.limit)
+	list := make([]Item, 0, len(found))
+	for _, item := range found {
+		list = append(list, *item)
+	}
+
+	return list, nil
+}
+
+func (m *MemoryStore) AutoArchive(_ context.Context, items []Item) ([]Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var archived []Item
+	delta := make(countersDelta)
+	now := time.Now()
+	for _, expired := range items {
+		idx, ok := m.index[expired.key()]
+		if !ok {
+			continue
+		}
+
+		item := m.items[idx]
+		if item.ID != expired.ID || item.ArchivedAt != nil || item.DeletedAt.Valid {
+			continue
+		}
+
+		before := *item
+		item.ArchivedAt = &now
+		item.ArchiveReason = ArchivedExpired
+		unsnooze(item)
+		item.UpdatedAt = now
+		delta.change(&before, item)
+		archived = append(archived, *item)
+	}
+
+	m.applyCountersDelta(delta)
+
+	if err := m.storeEvents(SubjectItemAutoArchived, archived); err != nil {
+		return nil, err
+	}
+
+	m.storeHistory(TransitionArchived, SourceWorker, archived, now)
+
+	return archived, nil
+}
+
+func (m *MemoryStore) GetFeedSettings(_ context.Context, subscriber uuid.UUID) (*Settings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set, ok := m.settings[subscriber]
+	if !ok {
+		return nil, fmt.Errorf("get settings by id #%s: %w", subscriber, gorm.ErrRecordNotFound)
+	}
+
+	return &set, nil
+}
+
+func (m *MemoryStore) SetDaoEvents(_ context.Context, subscriberID uuid.UUID, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	set, ok := m.settings[subscriberID]
+	if !ok {
+		set = defaultSettings(subscriberID)
+		set.CreatedAt = now
+	}
+
+	set.UpdatedAt = now
+	set.DaoEvents = enabled
+	m.settings[subscriberID] = set
+
+	if enabled {
+		return nil
+	}
+
+	return m.delete(subscriberID, func(item *Item) bool {
+		return item.DAO()
+	}, now)
+}
+
+func (m *MemoryStore) SetAutoArchiveRules(_ context.Context, subscriberID uuid.UUID, rules AutoArchiveRules) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	set, ok := m.settings[subscriberID]
+	if !ok {
+		set = defaultSettings(subscriberID)
+		set.CreatedAt = now
+	}
+
+	set.UpdatedAt = now
+	set.AutoarchiveRules = rules
+	m.settings[subscriberID] = set
+
+	return nil
+}
+
+func (m *MemoryStore) FindSettings(_ context.Context, subscriberIDs []uuid.UUID) ([]Settings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []Settings
+	for subscriberID, set := range m.settings {
+		if slices.Contains(subscriberIDs, subscriberID) {
+			list = append(list, set)
+		}
+	}
+
+	return list, nil
+}
+
+func (m *MemoryStore) FindDaoEventsSubscribers(_ context.Context, subscriberIDs []uuid.UUID) ([]uuid.UUID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var subscribers []uuid.UUID
+	for _, subscriberID := range subscriberIDs {
+		if m.settings[subscriberID].DaoEvents && !slices.Contains(subscribers, subscriberID) {
+			subscribers = append(subscribers, subscriberID)
+		}
+	}
+
+	return subscribers, nil
+}
+
+func (m *MemoryStore) StoreSettings(_ context.Context, sd *Settings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	set, ok := m.settings[sd.SubscriberID]
+	if !ok {
+		set = Settings{SubscriberID: sd.SubscriberID, CreatedAt: now}
+	}
+
+	set.UpdatedAt = now
+	set.AutoarchiveAfterDays = sd.AutoarchiveAfterDays
+	m.settings[sd.SubscriberID] = set
+
+	return nil
+}
+
+func (m *MemoryStore) PublishOutbox(_ context.Context, limit int, publish func(event OutboxEvent) error) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var published int
+	for _, event := range window(m.outbox, 0, limit) {
+		if err := publish(event); err != nil {
+			m.outbox = m.outbox[published:]
+			return published, fmt.Errorf("publish outbox event: %w", err)
+		}
+
+		published++
+	}
+
+	m.outbox = m.outbox[published:]
+
+	return published, nil
+}
+
+type backfillKey struct {
+	subscriberID uuid.UUID
+	daoID        uuid.UUID
+}
+
+func (m *MemoryStore) EnqueueBackfill(_ context.Context, subscriberID, daoID uuid.UUID) (BackfillJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := backfillKey{subscriberID: subscriberID, daoID: daoID}
+	stored, ok := m.backfills[key]
+	if ok && (stored.Status == BackfillPending || stored.Status == BackfillRunning) {
+		return *stored, nil
+	}
+
+	now := time.Now()
+	job := &BackfillJob{
+		SubscriberID: subscriberID,
+		DaoID:        daoID,
+		Status:       BackfillPending,
+		NextRunAt:    now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	// the claim is kept, so workers of previous runs can't save the job
+	if ok {
+		job.Claim = stored.Claim
+	}
+	m.backfills[key] = job
+
+	return *job, nil
+}
+
+func (m *MemoryStore) ClaimBackfills(_ context.Context, limit int, stalledBefore time.Time) ([]BackfillJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var ready []*BackfillJob
+	for _, job := range m.backfills {
+		pending := job.Status == BackfillPending && !job.NextRunAt.After(now)
+		stalled := job.Status == BackfillRunning && job.UpdatedAt.Before(stalledBefore)
+		if pending || stalled {
+			ready = append(ready, job)
+		}
+	}
+
+	slices.SortFunc(ready, func(a, b *BackfillJob) int {
+		return a.NextRunAt.Compare(b.NextRunAt)
+	})
+
+	jobs := make([]BackfillJob, 0, min(limit, len(ready)))
+	for _, job := range ready[:min(limit, len(ready))] {
+		job.Status = BackfillRunning
+		job.Claim++
+		job.UpdatedAt = now
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, nil
+}
+
+func (m *MemoryStore) SaveBackfill(_ context.Context, job *BackfillJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.backfills[backfillKey{subscriberID: job.SubscriberID, daoID: job.DaoID}]
+	if !ok || stored.Status != BackfillRunning || stored.Claim != job.Claim {
+		return ErrBackfillNotRunning
+	}
+
+	job.UpdatedAt = time.Now()
+	*stored = *job
+
+	return nil
+}
+
+func (m *MemoryStore) ReplaceBackfill(_ context.Context, job *BackfillJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := backfillKey{subscriberID: job.SubscriberID, daoID: job.DaoID}
+	if stored, ok := m.backfills[key]; ok {
+		job.Claim = stored.Claim + 1
+	}
+
+	job.UpdatedAt = time.Now()
+	stored := *job
+	m.backfills[key] = &stored
+
+	return nil
+}
+
+func (m *MemoryStore) FindBackfills(_ context.Context, subscriberID, daoID uuid.UUID) ([]BackfillJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var jobs []BackfillJob
+	for _, job := range m.backfills {
+		if job.SubscriberID == subscriberID && (daoID == uuid.Nil || job.DaoID == daoID) {
+			jobs = append(jobs, *job)
+		}
+	}
+
+	slices.SortFunc(jobs, func(a, b BackfillJob) int {
+		return bytes.Compare(a.DaoID[:], b.DaoID[:])
+	})
+
+	return jobs, nil
+}
+
+func (m *MemoryStore) FindItemHistory(_ context.Context, subscriberID, itemID uuid.UUID, before int64, limit int) ([]ItemHistoryEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []ItemHistoryEvent
+	for _, event := range slices.Backward(m.history) {
+		if len(list) == limit {
+			break
+		}
+
+		if event.SubscriberID != subscriberID || (itemID != uuid.Nil && event.ItemID != itemID) || (before > 0 && event.ID >= before) {
+			continue
+		}
+
+		list = append(list, event)
+	}
+
+	return list, nil
+}
+
+func (m *MemoryStore) TryLock(_ context.Context, key int64) (func() error, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.locks[key] {
+		return nil, false, nil
+	}
+
+	m.locks[key] = true
+
+	return func() error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.locks, key)
+
+		return nil
+	}, true, nil
+}
+
+func (m *MemoryStore) LastJobRun(_ context.Context, name string) (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.jobRuns[name], nil
+}
+
+func (m *MemoryStore) SaveJobRun(_ context.Context, name string, startedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobRuns[name] = startedAt
+
+	return nil
+}
+
+func (m *MemoryStore) storeEvents(subject string, items []Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	events, err := newItemEvents(subject, items, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		m.lastOutboxID++
+		event.ID = m.lastOutboxID
+		m.outbox = append(m.outbox, event)
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) storeHistory(transition ItemTransition, source TransitionSource, items []Item, occurredAt time.Time) {
+	for _, event := range newItemHistory(transition, source, items, occurredAt) {
+		m.lastHistoryID++
+		event.ID = m.lastHistoryID
+		m.history = append(m.history, event)
+	}
+}
+
+// memoryQuery is the in-memory counterpart of the SQL query built by filters.
+type memoryQuery struct {
+	conditions []func(item *Item) bool
+	orders     []func(a, b *Item) int
+	offset     int
+	limit      int
+}
+
+func newMemoryQuery(filters []Filter) *memoryQuery {
+	query := &memoryQuery{limit: -1}
+	for _, f := range filters {
+		f.memory(query)
+	}
+
+	return query
+}
+
+func (q *memoryQuery) where(condition func(item *Item) bool) {
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *memoryQuery) orderBy(order func(a, b *Item) int) {
+	q.orders = append(q.orders, order)
+}
+
+func (q *memoryQuery) match(item *Item) bool {
+	// soft deleted items are skipped by gorm
+	if item.DeletedAt.Valid {
+		return false
+	}
+
+	for _, condition := range q.conditions {
+		if !condition(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (q *memoryQuery) compare(a, b *Item) int {
+	for _, order := range q.orders {
+		if result := order(a, b); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+func window[T any](list []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(list) {
+			return nil
+		}
+
+		list = list[offset:]
+	}
+
+	if limit >= 0 && limit < len(list) {
+		list = list[:limit]
+	}
+
+	return list
+}
+
+// snapshotText returns the snapshot field as text in the same way as the snapshot->>'key' operator does.
+func snapshotText(item *Item, key string) (string, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(item.Snapshot, &fields); err != nil {
+		return "", false
+	}
+
+	raw, ok := fields[key]
+	if !ok || string(raw) == "null" {
+		return "", false
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, true
+	}
+
+	return string(raw), true
+}
+
+// actualityCursor returns the position of the item in the actuality order in the same way as SQL does.
+func actualityCursor(item *Item) Cursor {
+	state, _ := snapshotText(item, "state")
+
+	return Cursor{
+		Rank:    actualityRank(state),
+		Created: actualityCreated(item),
+		ID:      item.ID,
+	}
+}
@@ -12,8 +12,9 @@ import (
 )
 
 const (
-	Dao      Type = "dao"
-	Proposal Type = "proposal"
+	Dao        Type = "dao"
+	Proposal   Type = "proposal"
+	Discussion Type = "discussion"
 
 	DaoCreated                  Action = "dao.created"
 	DaoUpdated                  Action = "dao.updated"
@@ -24,17 +25,40 @@ const (
 	ProposalVotingStarted       Action = "proposal.voting.started"
 	ProposalVotingQuorumReached Action = "proposal.voting.quorum_reached"
 	ProposalVotingEnded         Action = "proposal.voting.ended"
+	DiscussionCreated           Action = "discussion.created"
+	DiscussionUpdated           Action = "discussion.updated"
+	DiscussionReplied           Action = "discussion.replied"
 )
 
 type Type string
 
 type Action string
 
+// ArchiveReason tells which path archived the item, it's empty for items archived before reasons were stored.
+type ArchiveReason string
+
+const (
+	// ArchivedManually items are archived by the subscriber
+	ArchivedManually ArchiveReason = "manual"
+	// ArchivedVoted items are archived after the vote if the subscriber enabled it in the inbox settings
+	ArchivedVoted ArchiveReason = "voted"
+	// ArchivedExpired items are archived by auto-archive rules
+	ArchivedExpired ArchiveReason = "expired"
+	// ArchivedBulkByTime items are archived by the subscriber with all items created before the time
+	ArchivedBulkByTime ArchiveReason = "bulk-by-time"
+	// ArchivedUnsubscribed items are archived when the subscriber left the dao in the archive mode
+	ArchivedUnsubscribed ArchiveReason = "unsubscribed"
+)
+
 type TimelineInfo struct {
 	CreatedAt time.Time `json:"created_at"`
 	Action    Action    `json:"action"`
 }
 
+func (i TimelineInfo) equal(other TimelineInfo) bool {
+	return i.Action == other.Action && i.CreatedAt.Equal(other.CreatedAt)
+}
+
 type Timeline []TimelineInfo
 
 func (t *Timeline) Sort() {
@@ -60,46 +84,134 @@ func (t Timeline) Equal(updated Timeline) bool {
 
 type Item struct {
 	ID           uuid.UUID `gorm:"primary_key" json:"id"`
-	SubscriberID uuid.UUID `gorm:"primary_key;uniqueIndex:feed_item_dao_proposal_uidx"`
+	SubscriberID uuid.UUID `gorm:"primary_key;uniqueIndex:feed_item_proposal_uidx,where:proposal_id <> '';uniqueIndex:feed_item_discussion_uidx,where:proposal_id = ''"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	DeletedAt    gorm.DeletedAt  `gorm:"index"`
 	ReadAt       *time.Time      `json:"read_at" gorm:"index"`
 	ArchivedAt   *time.Time      `json:"archived_at" gorm:"index"`
 	UnarchivedAt *time.Time      `json:"unarchived_at"`
-	DaoID        uuid.UUID       `json:"dao_id" gorm:"uniqueIndex:feed_item_dao_proposal_uidx"`
-	ProposalID   string          `json:"proposal_id" gorm:"uniqueIndex:feed_item_dao_proposal_uidx"`
-	DiscussionID string          `json:"discussion_id"`
+	DaoID        uuid.UUID       `json:"dao_id" gorm:"uniqueIndex:feed_item_proposal_uidx;uniqueIndex:feed_item_discussion_uidx"`
+	ProposalID   string          `json:"proposal_id" gorm:"uniqueIndex:feed_item_proposal_uidx"`
+	DiscussionID string          `json:"discussion_id" gorm:"uniqueIndex:feed_item_discussion_uidx"`
 	Type         Type            `json:"type"`
 	Action       Action          `json:"action"`
 	Snapshot     json.RawMessage `gorm:"type:jsonb;serializer:json" json:"dao,omitempty"`
 	Timeline     Timeline        `gorm:"type:jsonb;serializer:json" json:"timeline"`
+	// ResurfacedAt is the time of the last significant update which marked the item as unread
+	ResurfacedAt     *time.Time `json:"resurfaced_at"`
+	ResurfacedReason string     `json:"resurfaced_reason"`
+	// UnarchivedReason is manual or the timeline action which unarchived the item
+	UnarchivedReason string `json:"unarchived_reason"`
+	// ArchiveReason is set for archived items only
+	ArchiveReason ArchiveReason `json:"archive_reason"`
+	// VotedAt is the time when the subscriber voted on the proposal
+	VotedAt *time.Time `json:"voted_at"`
+	// FrozenAt is the time when the subscriber left the dao, frozen items aren't updated anymore
+	FrozenAt *time.Time `json:"frozen_at"`
+	// SnoozedAt is the time when the subscriber snoozed the item, snoozed items are hidden until they wake up
+	SnoozedAt *time.Time `json:"snoozed_at"`
+	// SnoozedUntil wakes up the snoozed item at the time
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+	// SnoozedUntilAction wakes up the snoozed item when the action appears in its timeline
+	SnoozedUntilAction Action `json:"snoozed_until_action"`
 }
 
+// defaultAutoarchiveAfterDays is used for subscribers without settings
+const defaultAutoarchiveAfterDays = 7
+
 type Settings struct {
 	SubscriberID         uuid.UUID `gorm:"index"`
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 	AutoarchiveAfterDays int
+	// DaoEvents means the subscriber opted in to DAO-level items, e.g. the renamed dao or new strategies
+	DaoEvents bool
+	// AutoarchiveRules replace the default rule based on AutoarchiveAfterDays when set
+	AutoarchiveRules AutoArchiveRules `gorm:"type:jsonb;serializer:json"`
+}
+
+func defaultSettings(subscriberID uuid.UUID) Settings {
+	return Settings{SubscriberID: subscriberID, AutoarchiveAfterDays: defaultAutoarchiveAfterDays}
+}
+
+// EffectiveAutoarchiveRules returns rules the auto-archive worker applies to the subscriber feed.
+func (s Settings) EffectiveAutoarchiveRules() AutoArchiveRules {
+	if len(s.AutoarchiveRules) == 0 {
+		return defaultAutoArchiveRules(s.AutoarchiveAfterDays)
+	}
+
+	return s.AutoarchiveRules
+}
+
+// DaoCounters contains the number of items and unread items of the dao in the subscriber feed.
+type DaoCounters struct {
+	DaoID       uuid.UUID
+	TotalCount  int64
+	UnreadCount int64
 }
 
+// DAO means the item is about the dao itself, there is the single DAO-level item per dao in the subscriber feed.
 func (i Item) DAO() bool {
 	return i.ProposalID == "" && i.DiscussionID == ""
 }
 
+// key identifies the item in the subscriber feed like unique indexes of items do: proposals by the proposal id,
+// because they could link the discussion later, and other items by the discussion id.
+func (i Item) key() itemKey {
+	if i.ProposalID != "" {
+		return itemKey{subscriberID: i.SubscriberID, daoID: i.DaoID, proposalID: i.ProposalID}
+	}
+
+	return itemKey{subscriberID: i.SubscriberID, daoID: i.DaoID, discussionID: i.DiscussionID}
+}
+
 const (
-	ProposalStateActive  = "active"
-	ProposalStatePending = "pending"
+	ProposalStateActive    = "active"
+	ProposalStatePending   = "pending"
+	ProposalStateSucceeded = "succeeded"
+	ProposalStateFailed    = "failed"
+	ProposalStateDefeated  = "defeated"
+	ProposalStateCanceled  = "canceled"
 )
 
 var (
+	// statelessTypes are types of items without the proposal state, the spam flag of them is optional
+	statelessTypes = []Type{Dao, Discussion}
+
 	activeProposalStates = []string{ProposalStateActive, ProposalStatePending}
+
+	// actualityProposalStates defines the order of proposals in the feed, unknown states go last
+	actualityProposalStates = []string{
+		ProposalStateActive,
+		ProposalStatePending,
+		ProposalStateSucceeded,
+		ProposalStateFailed,
+		ProposalStateDefeated,
+		ProposalStateCanceled,
+	}
 )
 
 type ShortProposalInfo struct {
-	State string `json:"state"`
+	State   string `json:"state"`
+	Created int64  `json:"created"`
 }
 
 func (i *ShortProposalInfo) Active() bool {
 	return slices.Contains(activeProposalStates, i.State)
 }
+
+// ActualityRank returns the position of the proposal state in the feed ordering starting from 1.
+// It must be in sync with the rank calculated by SortedByActuality.
+func (i *ShortProposalInfo) ActualityRank() int {
+	return actualityRank(i.State)
+}
+
+func actualityRank(state string) int {
+	idx := slices.Index(actualityProposalStates, state)
+	if idx == -1 {
+		return len(actualityProposalStates) + 1
+	}
+
+	return idx + 1
+}
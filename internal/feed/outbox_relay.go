@@ -0,0 +1,68 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	outboxBatchSize  = 100
+	outboxCheckDelay = time.Second
+)
+
+type Publisher interface {
+	PublishJSON(ctx context.Context, subject string, obj any) error
+}
+
+// OutboxStore gives access to not published events in the order they were stored.
+type OutboxStore interface {
+	// PublishOutbox passes up to limit oldest events to publish and removes the published ones.
+	// It stops on the first publish error, so the order of events is kept.
+	PublishOutbox(ctx context.Context, limit int, publish func(event OutboxEvent) error) (int, error)
+}
+
+// OutboxRelay publishes events stored in the outbox to NATS. Events are delivered at least once.
+type OutboxRelay struct {
+	store     OutboxStore
+	publisher Publisher
+}
+
+func NewOutboxRelay(store OutboxStore, publisher Publisher) *OutboxRelay {
+	return &OutboxRelay{
+		store:     store,
+		publisher: publisher,
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	for {
+		published, err := r.relay(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("publish outbox events")
+		}
+
+		// drain the outbox without delays while it's full
+		if err == nil && published == outboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(outboxCheckDelay):
+		}
+	}
+}
+
+func (r *OutboxRelay) relay(ctx context.Context) (int, error) {
+	return r.store.PublishOutbox(ctx, outboxBatchSize, func(event OutboxEvent) error {
+		payload, err := event.published()
+		if err != nil {
+			return err
+		}
+
+		return r.publisher.PublishJSON(ctx, event.Subject, payload)
+	})
+}
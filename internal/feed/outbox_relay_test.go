@@ -0,0 +1,66 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	subjects []string
+	payloads []json.RawMessage
+	err      error
+}
+
+func (f *fakePublisher) PublishJSON(_ context.Context, subject string, obj any) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	payload, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	f.subjects = append(f.subjects, subject)
+	f.payloads = append(f.payloads, payload)
+
+	return nil
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	item := storeTestItem(uuid.New(), "proposal", ProposalStateActive, 1)
+	require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{item}))
+	require.NoError(t, store.MarkAsReadByID(ctx, item.SubscriberID, SourceGRPC, item.ID))
+
+	publisher := &fakePublisher{err: errors.New("unavailable")}
+	relay := NewOutboxRelay(store, publisher)
+
+	published, err := relay.relay(ctx)
+	require.Error(t, err)
+	assert.Zero(t, published)
+
+	publisher.err = nil
+	published, err = relay.relay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{SubjectItemCreated, SubjectItemRead}, publisher.subjects)
+
+	var event ItemEvent
+	require.NoError(t, json.Unmarshal(publisher.payloads[1], &event))
+	assert.Equal(t, ItemEventVersion, event.Version)
+	assert.Equal(t, item.ID, event.ID)
+	assert.Equal(t, item.SubscriberID, event.SubscriberID)
+	assert.Equal(t, int64(2), event.EventID)
+
+	published, err = relay.relay(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, published, "published events are removed")
+}
@@ -1,11 +1,15 @@
 package feed
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,204 +17,1092 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// bulkUpsertChunkSize keeps the number of statement parameters far below the Postgres limit
+const bulkUpsertChunkSize = 500
+
+// proposalKeyConflict and discussionKeyConflict are targets of partial unique indexes which identify the item
+// in the subscriber feed, see Item.key
+var (
+	proposalKeyConflict = clause.OnConflict{
+		Columns:     []clause.Column{{Name: "subscriber_id"}, {Name: "dao_id"}, {Name: "proposal_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "proposal_id <> ''"}}},
+	}
+	discussionKeyConflict = clause.OnConflict{
+		Columns:     []clause.Column{{Name: "subscriber_id"}, {Name: "dao_id"}, {Name: "discussion_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "proposal_id = ''"}}},
+	}
+)
+
+// itemKeyExpr is the key of the stored item in the same form as Item.key
+const itemKeyExpr = "subscriber_id, dao_id, proposal_id, case when proposal_id = '' then discussion_id else '' end"
+
+// upsertAttempts limits retries of upserts which raced with concurrent inserts of the same items
+const upsertAttempts = 3
+
+// errConcurrentInsert means the item wasn't found before the insert, but was inserted by the concurrent transaction
+var errConcurrentInsert = errors.New("item is inserted concurrently")
+
 type Repo struct {
-	conn *gorm.DB
+	conn   *gorm.DB
+	policy UpdatePolicy
 }
 
-func NewRepo(conn *gorm.DB) *Repo {
-	return &Repo{conn: conn}
+func NewRepo(conn *gorm.DB, policy UpdatePolicy) *Repo {
+	return &Repo{
+		conn:   conn,
+		policy: policy,
+	}
+}
+
+// CreateOrUpdate upserts the item with the single statement like BulkCreateOrUpdate does
+// and sets the stored state to the item. Only the row of the subscriber is locked.
+func (r *Repo) CreateOrUpdate(ctx context.Context, item *Item) error {
+	now := time.Now()
+	item.UpdatedAt = now
+
+	return r.upsert(ctx, func(tx *gorm.DB) error {
+		stored, err := upsertChunk(tx, []Item{*item}, now, r.policy)
+		if err != nil {
+			return err
+		}
+
+		*item = stored[0]
+
+		return nil
+	})
 }
 
-func (r *Repo) CreateOrUpdate(item *Item) error {
+// BulkCreateOrUpdate upserts items by chunks using multi-row insert statements.
+// Items with the same key are collapsed to the last one,
+// because the single statement can't affect the same row twice.
+// Items are sorted by their keys, so concurrent upserts lock rows in the same order and don't deadlock.
+// All chunks and their events are stored in the single transaction.
+func (r *Repo) BulkCreateOrUpdate(ctx context.Context, items []Item) error {
 	var (
-		_ = item.DaoID
-		_ = item.ProposalID
-		_ = item.Snapshot
-		_ = item.Action
-		_ = item.Timeline
-		_ = item.CreatedAt
-		_ = item.UpdatedAt
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+		_     = dummy.ProposalID
+		_     = dummy.DiscussionID
+		_     = dummy.Snapshot
+		_     = dummy.Action
+		_     = dummy.Timeline
+		_     = dummy.CreatedAt
+		_     = dummy.UpdatedAt
+		_     = dummy.ReadAt
+		_     = dummy.ResurfacedAt
+		_     = dummy.ResurfacedReason
+		_     = dummy.ArchivedAt
+		_     = dummy.UnarchivedAt
+		_     = dummy.UnarchivedReason
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
 	)
 
-	// nolint:godox
-	// FIXME: Reset readAt if item was updated
-	// FIXME: Don't react if archivedAt is not null
+	items = uniqueItems(items)
+	if len(items) == 0 {
+		return nil
+	}
+	slices.SortFunc(items, compareItemKeys)
 
-	tx := r.conn.Begin()
+	now := time.Now()
 
-	var found Item
+	return r.upsert(ctx, func(tx *gorm.DB) error {
+		for chunk := range slices.Chunk(items, bulkUpsertChunkSize) {
+			if _, err := upsertChunk(tx, chunk, now, r.policy); err != nil {
+				return err
+			}
+		}
 
-	query := tx.
-		Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("dao_id = @dao_id and proposal_id = @proposal_id", sql.Named("dao_id", item.DaoID), sql.Named("proposal_id", item.ProposalID)).
-		First(&found)
+		return nil
+	})
+}
 
-	if query.Error != nil && !errors.Is(query.Error, gorm.ErrRecordNotFound) {
-		tx.Rollback()
-		return query.Error
+// upsert runs the upsert transaction, it's retried if items were inserted concurrently.
+// The next attempt finds them, because the insert waits for the concurrent transaction to finish.
+func (r *Repo) upsert(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	var err error
+	for range upsertAttempts {
+		err = r.conn.WithContext(ctx).Transaction(fn)
+		if !errors.Is(err, errConcurrentInsert) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// upsertChunk upserts items, the policy defines the read and archived state of existing items.
+// Items resurfaced or unarchived by the policy are appended to their history.
+// Existing rows are locked before the write to apply the policy and count the change, new rows are inserted
+// by the single statement and existing rows are updated by another one. Frozen items are skipped.
+// It isn't the single INSERT ... ON CONFLICT, because the policy, the counters delta and outbox subjects
+// depend on the stored state of each row which the conflict clause doesn't return. Only rows of the chunk
+// are locked, so concurrent upserts of other subscribers or proposals aren't blocked.
+// Returns stored items or errConcurrentInsert if the new item was inserted by the concurrent transaction.
+func upsertChunk(tx *gorm.DB, chunk []Item, now time.Time, policy UpdatePolicy) ([]Item, error) {
+	keys := make([][]any, 0, len(chunk))
+	for _, item := range chunk {
+		key := item.key()
+		keys = append(keys, []any{key.subscriberID, key.daoID, key.proposalID, key.discussionID})
 	}
 
-	timeline, err := json.Marshal(item.Timeline)
+	// soft deleted items are updated by the upsert as well
+	var existing []Item
+	err := tx.
+		Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("("+itemKeyExpr+") in ?", keys).
+		Order(itemKeyExpr).
+		Find(&existing).
+		Error
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("unalbe to marshal timeline: %w", err)
+		return nil, fmt.Errorf("find existing items: %w", err)
 	}
 
-	cl := clause.OnConflict{
-		Columns: []clause.Column{{Name: "subscriber_id"}, {Name: "dao_id"}, {Name: "proposal_id"}},
-		DoUpdates: clause.Set{
-			{Column: clause.Column{Name: "snapshot"}, Value: item.Snapshot},
-			{Column: clause.Column{Name: "timeline"}, Value: timeline},
-			{Column: clause.Column{Name: "action"}, Value: item.Action},
-			{Column: clause.Column{Name: "created_at"}, Value: item.CreatedAt},
-			{Column: clause.Column{Name: "updated_at"}, Value: time.Now()},
-		},
+	stored := make(map[itemKey]*Item, len(existing))
+	for i, item := range existing {
+		stored[item.key()] = &existing[i]
 	}
 
-	query = tx.Debug().Clauses(cl).Create(item)
+	var (
+		frozen []Item
+		// proposals and other items are written by different statements, because they have different unique indexes
+		createdProposals, createdOthers []Item
+		proposals, others               []Item
+		proposalOutcomes, otherOutcomes []updateOutcome
+	)
+	for _, item := range chunk {
+		before, ok := stored[item.key()]
+		if !ok && item.ProposalID != "" {
+			createdProposals = append(createdProposals, item)
+			continue
+		}
+		if !ok {
+			createdOthers = append(createdOthers, item)
+			continue
+		}
 
-	if query.Error != nil {
-		tx.Rollback()
-		return query.Error
+		outcome := policy.apply(before, &item, now)
+		if outcome == outcomeFrozen {
+			frozen = append(frozen, *before)
+			continue
+		}
+
+		if item.ProposalID != "" {
+			proposals = append(proposals, item)
+			proposalOutcomes = append(proposalOutcomes, outcome)
+			continue
+		}
+
+		others = append(others, item)
+		otherOutcomes = append(otherOutcomes, outcome)
+	}
+
+	created := slices.Concat(createdProposals, createdOthers)
+	for _, group := range []struct {
+		items    []Item
+		conflict clause.OnConflict
+	}{
+		{items: created[:len(createdProposals)], conflict: proposalKeyConflict},
+		{items: created[len(createdProposals):], conflict: discussionKeyConflict},
+	} {
+		if len(group.items) == 0 {
+			continue
+		}
+
+		// the insert waits for the concurrent transaction which inserts the same item and skips it,
+		// the whole transaction has to be retried to apply the update to the inserted row.
+		// Only conflicts on the item key are skipped, other ones fail the insert
+		cl := group.conflict
+		cl.DoNothing = true
+
+		query := tx.Clauses(cl).Create(&group.items)
+		if query.Error != nil {
+			return nil, query.Error
+		}
+
+		if query.RowsAffected != int64(len(group.items)) {
+			return nil, errConcurrentInsert
+		}
+	}
+
+	updated := slices.Concat(proposals, others)
+	outcomes := slices.Concat(proposalOutcomes, otherOutcomes)
+	for _, group := range []struct {
+		items    []Item
+		conflict clause.OnConflict
+	}{
+		{items: updated[:len(proposals)], conflict: proposalKeyConflict},
+		{items: updated[len(proposals):], conflict: discussionKeyConflict},
+	} {
+		if len(group.items) == 0 {
+			continue
+		}
+
+		// the discussion id of proposals is updated, because they could link the discussion later
+		cl := group.conflict
+		cl.DoUpdates = clause.AssignmentColumns([]string{
+			"discussion_id", "snapshot", "timeline", "action", "created_at", "updated_at", "read_at", "resurfaced_at", "resurfaced_reason",
+			"archived_at", "archive_reason", "unarchived_at", "unarchived_reason", "snoozed_at", "snoozed_until", "snoozed_until_action",
+		})
+
+		// rows are locked, so the statement always updates them. Stored items are returned to count them in their actual state
+		if err = tx.Clauses(cl, clause.Returning{}).Create(&group.items).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	byOutcome := make(map[string][]Item)
+	byTransition := make(map[ItemTransition][]Item)
+	delta := make(countersDelta)
+	for i := range created {
+		delta.change(nil, &created[i])
+	}
+	for i, item := range updated {
+		delta.change(stored[item.key()], &updated[i])
+
+		for _, subject := range outcomes[i].subjects() {
+			byOutcome[subject] = append(byOutcome[subject], item)
+		}
+		for _, transition := range outcomes[i].transitions() {
+			byTransition[transition] = append(byTransition[transition], item)
+		}
+	}
+
+	if err = applyCountersDelta(tx, delta, now); err != nil {
+		return nil, err
+	}
+
+	if err = storeItemEvents(tx, SubjectItemCreated, created, now); err != nil {
+		return nil, err
+	}
+
+	if err = storeItemEvents(tx, SubjectItemUpdated, updated, now); err != nil {
+		return nil, err
+	}
+
+	for _, subject := range outcomeSubjects {
+		if err = storeItemEvents(tx, subject, byOutcome[subject], now); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, transition := range outcomeTransitions {
+		if err = storeItemHistory(tx, transition, SourceFeedUpdate, byTransition[transition], now); err != nil {
+			return nil, err
+		}
 	}
 
-	return tx.Commit().Error
+	return slices.Concat(created, updated, frozen), nil
 }
 
-func (r *Repo) MarkAsReadByID(_ context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+// FindSubscribersByProposalID returns subscribers which have the not frozen proposal in their feed.
+func (r *Repo) FindSubscribersByProposalID(ctx context.Context, proposalID string) ([]uuid.UUID, error) {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
-		_     = dummy.ReadAt
+		_     = dummy.ProposalID
+		_     = dummy.FrozenAt
 	)
 
-	now := time.Now()
+	var subscribers []uuid.UUID
 	err := r.conn.
+		WithContext(ctx).
 		Model(&Item{}).
-		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("id in (@ids)", sql.Named("ids", id)).
-		Update("read_at", now).
+		Where("proposal_id = @proposal_id", sql.Named("proposal_id", proposalID)).
+		Where("frozen_at is null").
+		Distinct().
+		Pluck("subscriber_id", &subscribers).
 		Error
 
-	return err
+	return subscribers, err
 }
 
-func (r *Repo) MarkAsUnreadByID(_ context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+// FindSubscribersByDiscussionID returns subscribers which have the not frozen discussion in their feed.
+// Proposals could refer to the discussion as well, so only discussion items are taken into account.
+func (r *Repo) FindSubscribersByDiscussionID(ctx context.Context, discussionID string) ([]uuid.UUID, error) {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
-		_     = dummy.ReadAt
+		_     = dummy.Type
+		_     = dummy.DiscussionID
+		_     = dummy.FrozenAt
 	)
 
+	var subscribers []uuid.UUID
 	err := r.conn.
+		WithContext(ctx).
 		Model(&Item{}).
-		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("id in (@ids)", sql.Named("ids", id)).
-		Update("read_at", gorm.Expr("NULL")).
+		Where("type = @type", sql.Named("type", Discussion)).
+		Where("discussion_id = @discussion_id", sql.Named("discussion_id", discussionID)).
+		Where("frozen_at is null").
+		Distinct().
+		Pluck("subscriber_id", &subscribers).
 		Error
 
-	return err
+	return subscribers, err
 }
 
-func (r *Repo) MarkAsReadByTime(_ context.Context, subscriberID uuid.UUID, t time.Time) error {
+// FindSubscribersByDaoItem returns subscribers which have the not frozen DAO-level item of the dao in their feed.
+func (r *Repo) FindSubscribersByDaoItem(ctx context.Context, daoID uuid.UUID) ([]uuid.UUID, error) {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
-		_     = dummy.ReadAt
-		_     = dummy.CreatedAt
+		_     = dummy.DaoID
+		_     = dummy.ProposalID
+		_     = dummy.DiscussionID
+		_     = dummy.FrozenAt
 	)
 
-	now := time.Now()
+	var subscribers []uuid.UUID
 	err := r.conn.
+		WithContext(ctx).
 		Model(&Item{}).
-		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("updated_at <= @before", sql.Named("before", t)).
-		Update("read_at", now).
+		Scopes(daoItems).
+		Where("dao_id = @dao_id", sql.Named("dao_id", daoID)).
+		Where("frozen_at is null").
+		Distinct().
+		Pluck("subscriber_id", &subscribers).
 		Error
 
-	return err
+	return subscribers, err
+}
+
+// daoItems matches DAO-level items in the same way as Item.DAO does.
+func daoItems(query *gorm.DB) *gorm.DB {
+	return query.Where("proposal_id = '' and discussion_id = ''")
+}
+
+type itemKey struct {
+	subscriberID uuid.UUID
+	daoID        uuid.UUID
+	proposalID   string
+	discussionID string
+}
+
+// compareItemKeys orders items by their keys, so every transaction locks them in the same order.
+func compareItemKeys(a, b Item) int {
+	ka, kb := a.key(), b.key()
+	if c := bytes.Compare(ka.subscriberID[:], kb.subscriberID[:]); c != 0 {
+		return c
+	}
+
+	if c := bytes.Compare(ka.daoID[:], kb.daoID[:]); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(ka.proposalID, kb.proposalID); c != 0 {
+		return c
+	}
+
+	return strings.Compare(ka.discussionID, kb.discussionID)
+}
+
+func uniqueItems(items []Item) []Item {
+	positions := make(map[itemKey]int, len(items))
+	unique := make([]Item, 0, len(items))
+
+	for _, item := range items {
+		key := item.key()
+		if idx, ok := positions[key]; ok {
+			unique[idx] = item
+			continue
+		}
+
+		positions[key] = len(unique)
+		unique = append(unique, item)
+	}
+
+	return unique
 }
 
-func (r *Repo) MarkAsUnreadByTime(_ context.Context, subscriberID uuid.UUID, t time.Time) error {
+func (r *Repo) MarkAsReadByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
 		_     = dummy.ReadAt
-		_     = dummy.CreatedAt
 	)
 
-	now := time.Now()
-	err := r.conn.
-		Model(&Item{}).
-		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("updated_at >= @after", sql.Named("after", t)).
-		Update("read_at", now).
-		Error
+	return r.markItems(ctx, SubjectItemRead, TransitionRead, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("id in @ids", sql.Named("ids", id))
+	}, map[string]any{
+		"read_at": time.Now(),
+	})
+}
 
-	return err
+func (r *Repo) MarkAsUnreadByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ReadAt
+	)
+
+	return r.markItems(ctx, SubjectItemUnread, TransitionUnread, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("id in @ids", sql.Named("ids", id))
+	}, map[string]any{
+		"read_at": gorm.Expr("NULL"),
+	})
 }
 
-func (r *Repo) MarkAsArchivedByID(_ context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+// MarkAsReadByTime marks as read unread items updated before the time.
+func (r *Repo) MarkAsReadByTime(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ReadAt
+		_     = dummy.UpdatedAt
+	)
+
+	return r.markItems(ctx, SubjectItemRead, TransitionRead, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("updated_at <= @before", sql.Named("before", t)).
+			Where("read_at is null")
+	}, map[string]any{
+		"read_at": time.Now(),
+	})
+}
+
+// MarkAsUnreadByTime marks as unread read items updated after the time.
+func (r *Repo) MarkAsUnreadByTime(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ReadAt
+		_     = dummy.UpdatedAt
+	)
+
+	return r.markItems(ctx, SubjectItemUnread, TransitionUnread, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("updated_at >= @after", sql.Named("after", t)).
+			Where("read_at is not null")
+	}, map[string]any{
+		"read_at": gorm.Expr("NULL"),
+	})
+}
+
+// MarkAsArchivedByID archives items, the reason tells if the subscriber did it or it was done on their behalf.
+func (r *Repo) MarkAsArchivedByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, reason ArchiveReason, id ...uuid.UUID) error {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
 		_     = dummy.ArchivedAt
+		_     = dummy.ArchiveReason
 		_     = dummy.UnarchivedAt
+		_     = dummy.UnarchivedReason
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
 	)
 
-	err := r.conn.
+	return r.markItems(ctx, SubjectItemArchived, TransitionArchived, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("id in @ids", sql.Named("ids", id))
+	}, map[string]any{
+		"archived_at":          time.Now(),
+		"archive_reason":       reason,
+		"unarchived_at":        gorm.Expr("NULL"),
+		"unarchived_reason":    "",
+		"snoozed_at":           gorm.Expr("NULL"),
+		"snoozed_until":        gorm.Expr("NULL"),
+		"snoozed_until_action": "",
+	})
+}
+
+func (r *Repo) MarkAsUnarchivedByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ArchivedAt
+		_     = dummy.ArchiveReason
+		_     = dummy.UnarchivedAt
+		_     = dummy.UnarchivedReason
+	)
+
+	return r.markItems(ctx, SubjectItemUnarchived, TransitionUnarchived, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("id in @ids", sql.Named("ids", id))
+	}, map[string]any{
+		"archived_at":       gorm.Expr("NULL"),
+		"archive_reason":    "",
+		"unarchived_at":     time.Now(),
+		"unarchived_reason": UnarchivedManually,
+	})
+}
+
+// Snooze hides not archived items until they wake up, snoozed items are snoozed again with the new conditions.
+func (r *Repo) Snooze(ctx context.Context, subscriberID uuid.UUID, snooze Snooze, id ...uuid.UUID) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ArchivedAt
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
+	)
+
+	return r.updateItems(ctx, SubjectItemSnoozed, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("id in @ids", sql.Named("ids", id)).
+			Where("archived_at is null")
+	}, map[string]any{
+		"snoozed_at":           time.Now(),
+		"snoozed_until":        snooze.Until,
+		"snoozed_until_action": snooze.UntilAction,
+	})
+}
+
+// Unsnooze shows snoozed items again, their read state isn't changed.
+func (r *Repo) Unsnooze(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
+	)
+
+	return r.updateItems(ctx, SubjectItemUnsnoozed, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("id in @ids", sql.Named("ids", id)).
+			Where("snoozed_at is not null")
+	}, map[string]any{
+		"snoozed_at":           gorm.Expr("NULL"),
+		"snoozed_until":        gorm.Expr("NULL"),
+		"snoozed_until_action": "",
+	})
+}
+
+// WakeSnoozed brings snoozed items back as unread when their time comes and returns subscribers whose feed was changed.
+// Archived items aren't woken, the archive clears the snooze. Woken items are appended to their history as unread.
+func (r *Repo) WakeSnoozed(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	var (
+		dummy Item
+		_     = dummy.ReadAt
+		_     = dummy.ArchivedAt
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
+	)
+
+	var woken []Item
+	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		woken, err = updateItemsTx(tx, SubjectItemUnsnoozed, func(query *gorm.DB) *gorm.DB {
+			return query.
+				Where("snoozed_at is not null").
+				Where("archived_at is null").
+				Where("snoozed_until <= @now", sql.Named("now", now))
+		}, map[string]any{
+			"read_at":              gorm.Expr("NULL"),
+			"snoozed_at":           gorm.Expr("NULL"),
+			"snoozed_until":        gorm.Expr("NULL"),
+			"snoozed_until_action": "",
+		}, now)
+		if err != nil {
+			return err
+		}
+
+		return storeItemHistory(tx, TransitionUnread, SourceWorker, woken, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return itemSubscribers(woken), nil
+}
+
+// MarkAsVoted marks items of the proposal as voted by the subscriber. Voted items aren't unarchived by updates.
+// The vote isn't the change of the feed, so no events are stored.
+func (r *Repo) MarkAsVoted(ctx context.Context, subscriberID uuid.UUID, proposalID string) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ProposalID
+		_     = dummy.VotedAt
+	)
+
+	return r.conn.
+		WithContext(ctx).
 		Model(&Item{}).
 		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("id in (@ids)", sql.Named("ids", id)).
-		Update("archived_at", time.Now()).
-		Update("unarchived_at", gorm.Expr("NULL")).
+		Where("proposal_id = @proposal_id", sql.Named("proposal_id", proposalID)).
+		Where("voted_at is null").
+		UpdateColumn("voted_at", time.Now()).
 		Error
-
-	return err
 }
 
-func (r *Repo) MarkAsUnarchivedByID(_ context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+// Unsubscribe freezes items of the dao in the subscriber feed, the mode defines if they are removed or archived as well.
+// Unfinished backfill jobs of the dao are canceled.
+func (r *Repo) Unsubscribe(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, daoID uuid.UUID, mode UnsubscribeMode) error {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
+		_     = dummy.DaoID
 		_     = dummy.ArchivedAt
+		_     = dummy.ArchiveReason
 		_     = dummy.UnarchivedAt
+		_     = dummy.UnarchivedReason
+		_     = dummy.FrozenAt
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
 	)
 
-	err := r.conn.
+	byDao := func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("dao_id = @dao_id", sql.Named("dao_id", daoID))
+	}
+
+	now := time.Now()
+
+	return r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := cancelBackfills(tx, subscriberID, daoID, now); err != nil {
+			return err
+		}
+
+		switch mode {
+		case UnsubscribePurge:
+			return deleteItems(tx, byDao, now)
+		case UnsubscribeArchive:
+			archived, err := updateItemsTx(tx, SubjectItemArchived, func(query *gorm.DB) *gorm.DB {
+				return byDao(query).Where("archived_at is null")
+			}, map[string]any{
+				"archived_at":          now,
+				"archive_reason":       ArchivedUnsubscribed,
+				"unarchived_at":        gorm.Expr("NULL"),
+				"unarchived_reason":    "",
+				"snoozed_at":           gorm.Expr("NULL"),
+				"snoozed_until":        gorm.Expr("NULL"),
+				"snoozed_until_action": "",
+			}, now)
+			if err != nil {
+				return err
+			}
+
+			if err = storeItemHistory(tx, TransitionArchived, source, archived, now); err != nil {
+				return err
+			}
+		case UnsubscribeFreeze:
+		default:
+			return fmt.Errorf("unknown unsubscribe mode: %s", mode)
+		}
+
+		// the frozen state isn't visible to the subscriber, so no events are stored
+		return byDao(tx.Model(&Item{})).
+			Where("frozen_at is null").
+			UpdateColumn("frozen_at", now).
+			Error
+	})
+}
+
+// cancelBackfills stops pending and running jobs of the dao, running ones stop on their next save.
+func cancelBackfills(tx *gorm.DB, subscriberID, daoID uuid.UUID, now time.Time) error {
+	var (
+		dummy BackfillJob
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+		_     = dummy.Status
+		_     = dummy.UpdatedAt
+		_     = dummy.FinishedAt
+	)
+
+	return tx.
+		Model(&BackfillJob{}).
+		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+		Where("dao_id = @dao_id", sql.Named("dao_id", daoID)).
+		Where("status in @statuses", sql.Named("statuses", []BackfillStatus{BackfillPending, BackfillRunning})).
+		Updates(map[string]any{
+			"status":      BackfillCanceled,
+			"updated_at":  now,
+			"finished_at": now,
+		}).
+		Error
+}
+
+// Unfreeze allows updates of items of daos in the subscriber feed again.
+func (r *Repo) Unfreeze(ctx context.Context, subscriberID uuid.UUID, daoID ...uuid.UUID) error {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+		_     = dummy.FrozenAt
+	)
+
+	return r.conn.
+		WithContext(ctx).
 		Model(&Item{}).
 		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("id in (@ids)", sql.Named("ids", id)).
-		Update("archived_at", gorm.Expr("NULL")).
-		Update("unarchived_at", time.Now()).
+		Where("dao_id in @dao_ids", sql.Named("dao_ids", daoID)).
+		Where("frozen_at is not null").
+		UpdateColumn("frozen_at", gorm.Expr("NULL")).
 		Error
+}
 
-	return err
+// deleteItems removes items matched by the scope, their counters and stores events about them in the same transaction.
+func deleteItems(tx *gorm.DB, scope func(query *gorm.DB) *gorm.DB, now time.Time) error {
+	var deleted []Item
+	err := scope(tx.Unscoped().Model(&deleted).Clauses(clause.Returning{})).
+		Delete(&deleted).
+		Error
+	if err != nil {
+		return err
+	}
+
+	delta := make(countersDelta)
+	for i, item := range deleted {
+		// soft deleted items aren't counted
+		if !item.DeletedAt.Valid {
+			delta.change(&deleted[i], nil)
+		}
+	}
+
+	if err = applyCountersDelta(tx, delta, now); err != nil {
+		return err
+	}
+
+	return storeItemEvents(tx, SubjectItemDeleted, deleted, now)
 }
 
-func (r *Repo) MarkAsArchivedByTime(_ context.Context, subscriberID uuid.UUID, t time.Time) error {
+// MarkAsArchivedByTime archives not archived items created before the time.
+func (r *Repo) MarkAsArchivedByTime(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error {
 	var (
 		dummy Item
 		_     = dummy.SubscriberID
 		_     = dummy.CreatedAt
 		_     = dummy.ArchivedAt
+		_     = dummy.ArchiveReason
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
 	)
 
-	now := time.Now()
+	return r.markItems(ctx, SubjectItemArchived, TransitionArchived, source, func(query *gorm.DB) *gorm.DB {
+		return query.
+			Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+			Where("created_at <= @before", sql.Named("before", t)).
+			Where("archived_at is null")
+	}, map[string]any{
+		"archived_at":          time.Now(),
+		"archive_reason":       ArchivedBulkByTime,
+		"snoozed_at":           gorm.Expr("NULL"),
+		"snoozed_until":        gorm.Expr("NULL"),
+		"snoozed_until_action": "",
+	})
+}
+
+// updateItems updates items matched by the scope, their counters and stores events about them in the same transaction.
+// Gorm sets updated_at of changed items as well.
+func (r *Repo) updateItems(ctx context.Context, subject string, scope func(query *gorm.DB) *gorm.DB, values map[string]any) error {
+	return r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := updateItemsTx(tx, subject, scope, values, time.Now())
+
+		return err
+	})
+}
+
+// markItems does the same as updateItems and appends the transition of changed items to their history.
+func (r *Repo) markItems(ctx context.Context, subject string, transition ItemTransition, source TransitionSource, scope func(query *gorm.DB) *gorm.DB, values map[string]any) error {
+	return r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		changed, err := updateItemsTx(tx, subject, scope, values, now)
+		if err != nil {
+			return err
+		}
+
+		return storeItemHistory(tx, transition, source, changed, now)
+	})
+}
+
+// updateItemsTx does the same as updateItems in the given transaction and returns changed items.
+func updateItemsTx(tx *gorm.DB, subject string, scope func(query *gorm.DB) *gorm.DB, values map[string]any, now time.Time) ([]Item, error) {
+	// matched items are locked, so exactly the same items are changed and counted
+	var matched []Item
+	err := scope(tx.Model(&Item{}).Clauses(clause.Locking{Strength: "UPDATE"})).
+		Find(&matched).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	// the same item id is used in feeds of different subscribers
+	type itemRef struct {
+		id           uuid.UUID
+		subscriberID uuid.UUID
+	}
+
+	before := make(map[itemRef]*Item, len(matched))
+	ids := make([]uuid.UUID, 0, len(matched))
+	for i, item := range matched {
+		before[itemRef{id: item.ID, subscriberID: item.SubscriberID}] = &matched[i]
+		ids = append(ids, item.ID)
+	}
+
+	var changed []Item
+	err = scope(tx.Model(&changed).Clauses(clause.Returning{})).
+		Where("id in @locked_ids", sql.Named("locked_ids", ids)).
+		Updates(values).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	delta := make(countersDelta)
+	for i, item := range changed {
+		delta.change(before[itemRef{id: item.ID, subscriberID: item.SubscriberID}], &changed[i])
+	}
+
+	if err = applyCountersDelta(tx, delta, now); err != nil {
+		return nil, err
+	}
+
+	if err = storeItemEvents(tx, subject, changed, now); err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+// applyCountersDelta adds changes to counters of subscribers.
+func applyCountersDelta(tx *gorm.DB, delta countersDelta, now time.Time) error {
+	var (
+		dummy Counters
+		_     = dummy.TotalCount
+		_     = dummy.UnreadCount
+		_     = dummy.ArchivedCount
+		_     = dummy.UpdatedAt
+	)
+
+	list := delta.list()
+	if len(list) == 0 {
+		return nil
+	}
+
+	for i := range list {
+		list[i].UpdatedAt = now
+	}
+
+	cl := clause.OnConflict{
+		Columns: []clause.Column{{Name: "subscriber_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"total_count":    gorm.Expr("counters.total_count + excluded.total_count"),
+			"unread_count":   gorm.Expr("counters.unread_count + excluded.unread_count"),
+			"archived_count": gorm.Expr("counters.archived_count + excluded.archived_count"),
+			"updated_at":     gorm.Expr("excluded.updated_at"),
+		}),
+	}
+
+	if err := tx.Clauses(cl).CreateInBatches(list, bulkUpsertChunkSize).Error; err != nil {
+		return fmt.Errorf("update counters: %w", err)
+	}
+
+	return nil
+}
+
+// GetCounters returns counters of the subscriber feed, they are empty if the subscriber has no items.
+func (r *Repo) GetCounters(ctx context.Context, subscriberID uuid.UUID) (Counters, error) {
+	var counters Counters
 	err := r.conn.
-		Model(&Item{}).
+		WithContext(ctx).
 		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
-		Where("created_at <= @before", sql.Named("before", t)).
-		Update("archived_at", now).
+		Take(&counters).
 		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Counters{SubscriberID: subscriberID}, nil
+	}
+	if err != nil {
+		return Counters{}, fmt.Errorf("get counters by id #%s: %w", subscriberID, err)
+	}
 
-	return err
+	return counters, nil
+}
+
+// ReconcileCounters recalculates counters of up to limit subscribers following the given one in the id order.
+// Counters are locked before items are counted, so changes of items committed later are applied to fixed values.
+func (r *Repo) ReconcileCounters(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, []Counters, error) {
+	var (
+		dummy Item
+		_     = dummy.SubscriberID
+		_     = dummy.ReadAt
+		_     = dummy.ArchivedAt
+	)
+
+	var (
+		subscribers []uuid.UUID
+		fixed       []Counters
+	)
+
+	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`
+		select subscriber_id from items where subscriber_id > @after
+		union
+		select subscriber_id from counters where subscriber_id > @after
+		order by subscriber_id
+		limit @limit
+`, sql.Named("after", after), sql.Named("limit", limit)).Scan(&subscribers).Error
+		if err != nil {
+			return fmt.Errorf("find subscribers: %w", err)
+		}
+
+		if len(subscribers) == 0 {
+			return nil
+		}
+
+		empty := make([]Counters, 0, len(subscribers))
+		for _, id := range subscribers {
+			empty = append(empty, Counters{SubscriberID: id, UpdatedAt: time.Now()})
+		}
+
+		if err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&empty).Error; err != nil {
+			return fmt.Errorf("create counters: %w", err)
+		}
+
+		var stored []Counters
+		err = tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("subscriber_id in @ids", sql.Named("ids", subscribers)).
+			Order("subscriber_id").
+			Find(&stored).
+			Error
+		if err != nil {
+			return fmt.Errorf("lock counters: %w", err)
+		}
+
+		query := tx.Model(&Item{})
+		for _, f := range countedFilters() {
+			query = f.db(query)
+		}
+
+		var actual []Counters
+		err = query.
+			Select(`subscriber_id,
+				count(*) filter (where archived_at is null) as total_count,
+				count(*) filter (where archived_at is null and read_at is null) as unread_count,
+				count(*) filter (where archived_at is not null) as archived_count`).
+			Where("subscriber_id in @ids", sql.Named("ids", subscribers)).
+			Group("subscriber_id").
+			Scan(&actual).
+			Error
+		if err != nil {
+			return fmt.Errorf("count items: %w", err)
+		}
+
+		counted := make(map[uuid.UUID]Counters, len(actual))
+		for _, c := range actual {
+			counted[c.SubscriberID] = c
+		}
+
+		for _, c := range stored {
+			expected := counted[c.SubscriberID]
+			expected.SubscriberID = c.SubscriberID
+			if sameCounts(c, expected) {
+				continue
+			}
+
+			err = tx.
+				Model(&Counters{}).
+				Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", c.SubscriberID)).
+				Updates(map[string]any{
+					"total_count":    expected.TotalCount,
+					"unread_count":   expected.UnreadCount,
+					"archived_count": expected.ArchivedCount,
+				}).
+				Error
+			if err != nil {
+				return fmt.Errorf("fix counters of %s: %w", c.SubscriberID, err)
+			}
+
+			fixed = append(fixed, expected)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subscribers, fixed, nil
+}
+
+func storeItemEvents(tx *gorm.DB, subject string, items []Item, occurredAt time.Time) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	events, err := newItemEvents(subject, items, occurredAt)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.CreateInBatches(events, bulkUpsertChunkSize).Error; err != nil {
+		return fmt.Errorf("store %s events: %w", subject, err)
+	}
+
+	return nil
 }
 
-func (r *Repo) CountByFilters(_ context.Context, filters []Filter) (int64, error) {
-	query := r.conn.Model(&Item{})
+func storeItemHistory(tx *gorm.DB, transition ItemTransition, source TransitionSource, items []Item, occurredAt time.Time) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := tx.CreateInBatches(newItemHistory(transition, source, items, occurredAt), bulkUpsertChunkSize).Error; err != nil {
+		return fmt.Errorf("store %s history: %w", transition, err)
+	}
+
+	return nil
+}
+
+// FindItemHistory returns up to limit transitions of the subscriber items from the newest to the oldest.
+// Zero before means the first page, uuid.Nil item id means all items.
+func (r *Repo) FindItemHistory(ctx context.Context, subscriberID, itemID uuid.UUID, before int64, limit int) ([]ItemHistoryEvent, error) {
+	var (
+		dummy ItemHistoryEvent
+		_     = dummy.ID
+		_     = dummy.SubscriberID
+		_     = dummy.ItemID
+	)
+
+	query := r.conn.
+		WithContext(ctx).
+		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID))
+	if itemID != uuid.Nil {
+		query = query.Where("item_id = @item_id", sql.Named("item_id", itemID))
+	}
+	if before > 0 {
+		query = query.Where("id < @before", sql.Named("before", before))
+	}
+
+	var list []ItemHistoryEvent
+	err := query.
+		Order("id desc").
+		Limit(limit).
+		Find(&list).
+		Error
+
+	return list, err
+}
+
+func (r *Repo) CountByFilters(ctx context.Context, filters []Filter) (int64, error) {
+	query := r.conn.WithContext(ctx).Model(&Item{})
 	for _, f := range filters {
-		f(query)
+		query = f.db(query)
 	}
 
 	var count int64
@@ -219,10 +1111,34 @@ func (r *Repo) CountByFilters(_ context.Context, filters []Filter) (int64, error
 	return count, err
 }
 
-func (r *Repo) FindByFilters(_ context.Context, filters []Filter) ([]Item, error) {
-	query := r.conn.Model(&Item{})
+// CountByDao returns the number of all and unread items matched by filters grouped by dao and sorted by dao id.
+func (r *Repo) CountByDao(ctx context.Context, filters []Filter) ([]DaoCounters, error) {
+	var (
+		dummy Item
+		_     = dummy.DaoID
+		_     = dummy.ReadAt
+	)
+
+	query := r.conn.WithContext(ctx).Model(&Item{})
+	for _, f := range filters {
+		query = f.db(query)
+	}
+
+	var list []DaoCounters
+	err := query.
+		Select("dao_id, count(*) as total_count, count(*) filter (where read_at is null) as unread_count").
+		Group("dao_id").
+		Order("dao_id").
+		Scan(&list).
+		Error
+
+	return list, err
+}
+
+func (r *Repo) FindByFilters(ctx context.Context, filters []Filter) ([]Item, error) {
+	query := r.conn.WithContext(ctx).Model(&Item{})
 	for _, f := range filters {
-		f(query)
+		query = f.db(query)
 	}
 
 	var list []Item
@@ -231,35 +1147,81 @@ func (r *Repo) FindByFilters(_ context.Context, filters []Filter) ([]Item, error
 	return list, err
 }
 
-func (r *Repo) AutoArchive(_ context.Context) error {
+// AutoArchive archives items found by auto-archive rules if they are still not archived and returns archived items.
+// The change is recorded in the item history with SourceWorker.
+func (r *Repo) AutoArchive(ctx context.Context, items []Item) ([]Item, error) {
 	var (
 		dummy Item
+		_     = dummy.SubscriberID
 		_     = dummy.ArchivedAt
-		_     = dummy.Snapshot
+		_     = dummy.ArchiveReason
+		_     = dummy.SnoozedAt
+		_     = dummy.SnoozedUntil
+		_     = dummy.SnoozedUntilAction
 	)
 
-	return r.conn.Exec(`
-		update items fi
-		set archived_at = now()
-		from (select fi.id,
-					 fi.subscriber_id,
-					 coalesce(fs.autoarchive_after_days, 7)                                  as autoarchive_after_days,
-					 now()::date - to_timestamp((snapshot -> 'end')::double precision)::date as expired_days
-			  from items fi
-					   left join settings fs on fs.subscriber_id = fi.subscriber_id
-			  where fi.archived_at is null
-				AND fi.deleted_at is null
-				AND fi.created_at < now() - INTERVAL '1 day'
-				AND now()::date - to_timestamp((snapshot -> 'end')::double precision)::date > 0) ds
-		where ds.expired_days > ds.autoarchive_after_days
-		  and fi.id = ds.id
-		  and fi.subscriber_id = ds.subscriber_id
-`).Error
-}
-
-func (r *Repo) GetFeedSettings(_ context.Context, subscriber uuid.UUID) (*Settings, error) {
-	fs := Settings{SubscriberID: subscriber}
-	request := r.conn.Take(&fs)
+	bySubscriber := make(map[uuid.UUID][]uuid.UUID)
+	for _, item := range items {
+		bySubscriber[item.SubscriberID] = append(bySubscriber[item.SubscriberID], item.ID)
+	}
+
+	now := time.Now()
+
+	var archived []Item
+	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for subscriberID, ids := range bySubscriber {
+			changed, err := updateItemsTx(tx, SubjectItemAutoArchived, func(query *gorm.DB) *gorm.DB {
+				return query.
+					Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+					Where("id in @ids", sql.Named("ids", ids)).
+					Where("archived_at is null")
+			}, map[string]any{
+				"archived_at":          now,
+				"archive_reason":       ArchivedExpired,
+				"snoozed_at":           gorm.Expr("NULL"),
+				"snoozed_until":        gorm.Expr("NULL"),
+				"snoozed_until_action": "",
+			}, now)
+			if err != nil {
+				return err
+			}
+
+			if err = storeItemHistory(tx, TransitionArchived, SourceWorker, changed, now); err != nil {
+				return err
+			}
+
+			archived = append(archived, changed...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+// itemSubscribers returns sorted unique subscribers of items.
+func itemSubscribers(items []Item) []uuid.UUID {
+	subscribers := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		subscribers = append(subscribers, item.SubscriberID)
+	}
+
+	slices.SortFunc(subscribers, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	return slices.Compact(subscribers)
+}
+
+func (r *Repo) GetFeedSettings(ctx context.Context, subscriber uuid.UUID) (*Settings, error) {
+	var fs Settings
+	request := r.conn.
+		WithContext(ctx).
+		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriber)).
+		Take(&fs)
 	if err := request.Error; err != nil {
 		return nil, fmt.Errorf("get settings by id #%s: %w", subscriber, err)
 	}
@@ -267,8 +1229,122 @@ func (r *Repo) GetFeedSettings(_ context.Context, subscriber uuid.UUID) (*Settin
 	return &fs, nil
 }
 
-func (r *Repo) StoreSettings(_ context.Context, sd *Settings) error {
+// SetDaoEvents stores the opt-in to DAO-level items, existing DAO-level items are removed on opt-out.
+func (r *Repo) SetDaoEvents(ctx context.Context, subscriberID uuid.UUID, enabled bool) error {
+	var (
+		dummy Settings
+		_     = dummy.DaoEvents
+	)
+
+	now := time.Now()
+
+	return r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := updateSettingsTx(tx, subscriberID, map[string]any{"dao_events": enabled}, func(set *Settings) {
+			set.DaoEvents = enabled
+		}, now)
+		if err != nil {
+			return err
+		}
+
+		if enabled {
+			return nil
+		}
+
+		return deleteItems(tx, func(query *gorm.DB) *gorm.DB {
+			return daoItems(query.Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)))
+		}, now)
+	})
+}
+
+// SetAutoArchiveRules stores auto-archive rules of the subscriber, empty rules restore the default one.
+func (r *Repo) SetAutoArchiveRules(ctx context.Context, subscriberID uuid.UUID, rules AutoArchiveRules) error {
+	var (
+		dummy Settings
+		_     = dummy.AutoarchiveRules
+	)
+
+	value, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("marshal auto-archive rules: %w", err)
+	}
+
+	return r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateSettingsTx(tx, subscriberID, map[string]any{"autoarchive_rules": string(value)}, func(set *Settings) {
+			set.AutoarchiveRules = rules
+		}, time.Now())
+	})
+}
+
+// updateSettingsTx updates settings of the subscriber, default settings changed by the create func are stored
+// if there are no settings yet.
+func updateSettingsTx(tx *gorm.DB, subscriberID uuid.UUID, values map[string]any, create func(set *Settings), now time.Time) error {
+	var (
+		dummy Settings
+		_     = dummy.UpdatedAt
+	)
+
+	values["updated_at"] = now
+	query := tx.
+		Model(&Settings{}).
+		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+		Updates(values)
+	if query.Error != nil {
+		return query.Error
+	}
+
+	if query.RowsAffected > 0 {
+		return nil
+	}
+
+	set := defaultSettings(subscriberID)
+	set.CreatedAt = now
+	set.UpdatedAt = now
+	create(&set)
+
+	return tx.Create(&set).Error
+}
+
+// FindSettings returns stored settings of subscribers from the list.
+func (r *Repo) FindSettings(ctx context.Context, subscriberIDs []uuid.UUID) ([]Settings, error) {
+	var (
+		dummy Settings
+		_     = dummy.SubscriberID
+	)
+
+	var list []Settings
+	err := r.conn.
+		WithContext(ctx).
+		Where("subscriber_id in @subscriber_ids", sql.Named("subscriber_ids", subscriberIDs)).
+		Find(&list).
+		Error
+
+	return list, err
+}
+
+// FindDaoEventsSubscribers returns subscribers from the list which opted in to DAO-level items.
+func (r *Repo) FindDaoEventsSubscribers(ctx context.Context, subscriberIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var (
+		dummy Settings
+		_     = dummy.SubscriberID
+		_     = dummy.DaoEvents
+	)
+
+	var subscribers []uuid.UUID
 	err := r.conn.
+		WithContext(ctx).
+		Model(&Settings{}).
+		Where("subscriber_id in @subscriber_ids", sql.Named("subscriber_ids", subscriberIDs)).
+		Where("dao_events").
+		Distinct().
+		Pluck("subscriber_id", &subscribers).
+		Error
+
+	return subscribers, err
+}
+
+func (r *Repo) StoreSettings(ctx context.Context, sd *Settings) error {
+	err := r.conn.
+		WithContext(ctx).
 		Model(&Settings{}).
 		Where(&Settings{SubscriberID: sd.SubscriberID}).
 		Updates(&Settings{
@@ -280,3 +1356,283 @@ func (r *Repo) StoreSettings(_ context.Context, sd *Settings) error {
 
 	return err
 }
+
+// PublishOutbox passes the oldest stored events to publish and removes the published ones.
+// Locked events are skipped, so a few relays could work in parallel.
+func (r *Repo) PublishOutbox(ctx context.Context, limit int, publish func(event OutboxEvent) error) (int, error) {
+	var (
+		published  []int64
+		publishErr error
+	)
+
+	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events []OutboxEvent
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Order("id").
+			Limit(limit).
+			Find(&events).
+			Error
+		if err != nil {
+			return fmt.Errorf("find outbox events: %w", err)
+		}
+
+		for _, event := range events {
+			if publishErr = publish(event); publishErr != nil {
+				break
+			}
+
+			published = append(published, event.ID)
+		}
+
+		if len(published) == 0 {
+			return nil
+		}
+
+		// already published events are removed even if the rest of them failed
+		return tx.Where("id in @ids", sql.Named("ids", published)).Delete(&OutboxEvent{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if publishErr != nil {
+		return len(published), fmt.Errorf("publish outbox event: %w", publishErr)
+	}
+
+	return len(published), nil
+}
+
+func (r *Repo) EnqueueBackfill(ctx context.Context, subscriberID, daoID uuid.UUID) (BackfillJob, error) {
+	var (
+		dummy BackfillJob
+		_     = dummy.Status
+		_     = dummy.Total
+		_     = dummy.Processed
+		_     = dummy.Attempts
+		_     = dummy.Error
+		_     = dummy.NextRunAt
+		_     = dummy.CreatedAt
+		_     = dummy.UpdatedAt
+		_     = dummy.FinishedAt
+	)
+
+	now := time.Now()
+	job := BackfillJob{
+		SubscriberID: subscriberID,
+		DaoID:        daoID,
+		Status:       BackfillPending,
+		NextRunAt:    now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	// only finished jobs are started again
+	cl := clause.OnConflict{
+		Columns: []clause.Column{{Name: "subscriber_id"}, {Name: "dao_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "total", "processed", "attempts", "error", "next_run_at", "created_at", "updated_at", "finished_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			gorm.Expr("backfill_jobs.status in ?", []BackfillStatus{BackfillCompleted, BackfillFailed, BackfillCanceled}),
+		}},
+	}
+
+	query := r.conn.WithContext(ctx).Clauses(cl, clause.Returning{}).Create(&job)
+	if query.Error != nil {
+		return job, query.Error
+	}
+
+	if query.RowsAffected > 0 {
+		return job, nil
+	}
+
+	err := r.conn.
+		WithContext(ctx).
+		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+		Where("dao_id = @dao_id", sql.Named("dao_id", daoID)).
+		First(&job).
+		Error
+
+	return job, err
+}
+
+func (r *Repo) ClaimBackfills(ctx context.Context, limit int, stalledBefore time.Time) ([]BackfillJob, error) {
+	var (
+		dummy BackfillJob
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+		_     = dummy.Status
+		_     = dummy.Claim
+		_     = dummy.NextRunAt
+		_     = dummy.UpdatedAt
+	)
+
+	// skip locked allows instances to claim jobs concurrently, the new claim fences the worker of the stalled one
+	var jobs []BackfillJob
+	err := r.conn.
+		WithContext(ctx).
+		Raw(`
+			update backfill_jobs
+			set status = @running, updated_at = @now, claim = claim + 1
+			where (subscriber_id, dao_id) in (
+				select subscriber_id, dao_id
+				from backfill_jobs
+				where (status = @pending and next_run_at <= @now)
+				   or (status = @running and updated_at < @stalled_before)
+				order by next_run_at
+				limit @limit
+				for update skip locked
+			)
+			returning *`,
+			sql.Named("running", BackfillRunning),
+			sql.Named("pending", BackfillPending),
+			sql.Named("now", time.Now()),
+			sql.Named("stalled_before", stalledBefore),
+			sql.Named("limit", limit),
+		).
+		Scan(&jobs).
+		Error
+
+	return jobs, err
+}
+
+// SaveBackfill updates the job only while it's running by the same claim, so neither the canceled job is started again
+// by its worker nor the worker of the stalled job overwrites the progress of the one which claimed it again.
+func (r *Repo) SaveBackfill(ctx context.Context, job *BackfillJob) error {
+	var (
+		dummy BackfillJob
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+		_     = dummy.Status
+		_     = dummy.Claim
+	)
+
+	query := r.conn.
+		WithContext(ctx).
+		Model(job).
+		Where("status = @running", sql.Named("running", BackfillRunning)).
+		Where("claim = @claim", sql.Named("claim", job.Claim)).
+		Select("status", "total", "processed", "attempts", "error", "next_run_at", "updated_at", "finished_at").
+		Updates(job)
+	if query.Error != nil {
+		return query.Error
+	}
+
+	if query.RowsAffected == 0 {
+		return ErrBackfillNotRunning
+	}
+
+	return nil
+}
+
+// ReplaceBackfill upserts the job and increases its claim, so the worker of the running job can't save it anymore.
+func (r *Repo) ReplaceBackfill(ctx context.Context, job *BackfillJob) error {
+	var (
+		dummy BackfillJob
+		_     = dummy.Status
+		_     = dummy.Total
+		_     = dummy.Processed
+		_     = dummy.Attempts
+		_     = dummy.Error
+		_     = dummy.Claim
+		_     = dummy.NextRunAt
+		_     = dummy.CreatedAt
+		_     = dummy.UpdatedAt
+		_     = dummy.FinishedAt
+	)
+
+	job.UpdatedAt = time.Now()
+	cl := clause.OnConflict{
+		Columns: []clause.Column{{Name: "subscriber_id"}, {Name: "dao_id"}},
+		DoUpdates: append(clause.AssignmentColumns([]string{
+			"status", "total", "processed", "attempts", "error", "next_run_at", "created_at", "updated_at", "finished_at",
+		}), clause.Assignment{Column: clause.Column{Name: "claim"}, Value: gorm.Expr("backfill_jobs.claim + 1")}),
+	}
+
+	return r.conn.WithContext(ctx).Clauses(cl, clause.Returning{}).Create(job).Error
+}
+
+func (r *Repo) FindBackfills(ctx context.Context, subscriberID, daoID uuid.UUID) ([]BackfillJob, error) {
+	var (
+		dummy BackfillJob
+		_     = dummy.SubscriberID
+		_     = dummy.DaoID
+	)
+
+	query := r.conn.
+		WithContext(ctx).
+		Where("subscriber_id = @subscriber_id", sql.Named("subscriber_id", subscriberID)).
+		Order("dao_id")
+	if daoID != uuid.Nil {
+		query = query.Where("dao_id = @dao_id", sql.Named("dao_id", daoID))
+	}
+
+	var jobs []BackfillJob
+	err := query.Find(&jobs).Error
+
+	return jobs, err
+}
+
+// TryLock takes the session-level advisory lock on the dedicated connection, so the lock is held until it's released
+// or the instance dies. Returns false if the lock is held by another session.
+func (r *Repo) TryLock(ctx context.Context, key int64) (func() error, bool, error) {
+	db, err := r.conn.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("get connection: %w", err)
+	}
+
+	var acquired bool
+	if err = conn.QueryRowContext(ctx, "select pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil || !acquired {
+		return nil, false, errors.Join(err, conn.Close())
+	}
+
+	release := func() error {
+		// the context of the locked job could be canceled already
+		_, err := conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", key)
+		if err != nil {
+			// the lock is held by the session, so the connection mustn't be returned to the pool
+			_ = conn.Raw(func(any) error {
+				return driver.ErrBadConn
+			})
+
+			return fmt.Errorf("advisory unlock: %w", err)
+		}
+
+		return conn.Close()
+	}
+
+	return release, true, nil
+}
+
+func (r *Repo) LastJobRun(ctx context.Context, name string) (time.Time, error) {
+	var (
+		dummy JobRun
+		_     = dummy.Name
+		_     = dummy.StartedAt
+	)
+
+	var run JobRun
+	err := r.conn.
+		WithContext(ctx).
+		Where("name = @name", sql.Named("name", name)).
+		Limit(1).
+		Find(&run).
+		Error
+
+	return run.StartedAt, err
+}
+
+func (r *Repo) SaveJobRun(ctx context.Context, name string, startedAt time.Time) error {
+	cl := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"started_at"}),
+	}
+
+	return r.conn.WithContext(ctx).Clauses(cl).Create(&JobRun{Name: name, StartedAt: startedAt}).Error
+}
@@ -0,0 +1,45 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniqueItems(t *testing.T) {
+	subscriber, dao := uuid.New(), uuid.New()
+
+	first := Item{SubscriberID: subscriber, DaoID: dao, ProposalID: "1", Action: ProposalCreated}
+	second := Item{SubscriberID: subscriber, DaoID: dao, ProposalID: "2", Action: ProposalCreated}
+	firstUpdated := Item{SubscriberID: subscriber, DaoID: dao, ProposalID: "1", Action: ProposalUpdated}
+	other := Item{SubscriberID: uuid.New(), DaoID: dao, ProposalID: "1", Action: ProposalCreated}
+	linked := Item{SubscriberID: subscriber, DaoID: dao, ProposalID: "1", DiscussionID: "d", Action: ProposalUpdated}
+	discussion := Item{SubscriberID: subscriber, DaoID: dao, DiscussionID: "d", Action: DiscussionCreated}
+
+	for name, tc := range map[string]struct {
+		input  []Item
+		output []Item
+	}{
+		"empty": {
+			input:  nil,
+			output: []Item{},
+		},
+		"unique items": {
+			input:  []Item{first, second, other},
+			output: []Item{first, second, other},
+		},
+		"the last duplicate wins": {
+			input:  []Item{first, second, firstUpdated},
+			output: []Item{firstUpdated, second},
+		},
+		"proposals are identified without the discussion": {
+			input:  []Item{first, discussion, linked},
+			output: []Item{linked, discussion},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.output, uniqueItems(tc.input))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package feed
 import (
 	"context"
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,7 +17,10 @@ import (
 	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
 )
 
-const defaultPageLimit = 100
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 200
+)
 
 type Server struct {
 	inboxapi.UnimplementedFeedServer
@@ -36,44 +40,38 @@ func (s *Server) GetUserFeed(ctx context.Context, req *inboxapi.GetUserFeedReque
 		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
 	}
 
-	filters := []Filter{
-		SkipSpammed(),
-		SkipCanceled(),
-		SortedByActuality(),
-	}
-
-	var unreadStateFilters []Filter
-
-	switch req.GetReadState() {
-	case inboxapi.GetUserFeedRequest_Exclude:
-		unreadStateFilters = append(unreadStateFilters, FilterByReadStatus(helpers.Ptr(false)))
-	case inboxapi.GetUserFeedRequest_ExcludeOther:
-		unreadStateFilters = append(unreadStateFilters, FilterByReadStatus(helpers.Ptr(true)))
-	default:
-		// GetUserFeedRequest_Include is default behaviour
-	}
-
-	switch req.GetArchivedState() {
-	case inboxapi.GetUserFeedRequest_Exclude:
-		filters = append(filters, FilterByArchivedStatus(helpers.Ptr(false)))
-	case inboxapi.GetUserFeedRequest_ExcludeOther:
-		filters = append(filters, FilterByArchivedStatus(helpers.Ptr(true)))
-	default:
-		// GetUserFeedRequest_Include is default behaviour
-	}
+	// the offset paged feed keeps its order for existing clients
+	filters, unreadStateFilters := userFeedFilters(SortedByLegacyActuality(), req.GetReadState(), req.GetArchivedState())
+	// snoozed items are hidden from the shared protocol until they wake up
+	filters = append(filters, FilterBySnoozedStatus(helpers.Ptr(false)))
 
 	var pageLimit = defaultPageLimit
 	if req.GetLimit() > 0 {
-		// TODO: Limit max value
-		pageLimit = int(req.GetLimit())
+		pageLimit = min(int(req.GetLimit()), maxPageLimit)
 	}
 
-	totalCount, err := s.service.CountByFilters(ctx, subscriberID, append(filters, unreadStateFilters...))
+	// filters of the legacy feed match materialized counters unless archived items are requested
+	counters, err := s.service.GetCounters(ctx, subscriberID)
 	if err != nil {
-		log.Error().Err(err).Msg("unable to get total count of feed events")
+		log.Error().Err(err).Msg("unable to get feed counters")
 		return nil, status.Error(codes.Internal, "something went wrong")
 	}
 
+	totalCount, unreadCount, materialized := materializedCounts(counters, req.GetReadState(), req.GetArchivedState())
+	if !materialized {
+		totalCount, err = s.service.CountByFilters(ctx, subscriberID, slices.Concat(filters, unreadStateFilters))
+		if err != nil {
+			log.Error().Err(err).Msg("unable to get total count of feed events")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+
+		unreadCount, err = s.service.CountByFilters(ctx, subscriberID, slices.Concat(filters, []Filter{FilterByReadStatus(helpers.Ptr(false))}))
+		if err != nil {
+			log.Error().Err(err).Msg("unable to get unread count of feed events")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+	}
+
 	var pageOffset int
 	if req.GetOffset() > 0 {
 		pageOffset = int(req.GetOffset())
@@ -81,12 +79,6 @@ func (s *Server) GetUserFeed(ctx context.Context, req *inboxapi.GetUserFeedReque
 
 	filters = append(filters, WithLimit(pageLimit, pageOffset))
 
-	unreadCount, err := s.service.CountByFilters(ctx, subscriberID, append(filters, FilterByReadStatus(helpers.Ptr(false))))
-	if err != nil {
-		log.Error().Err(err).Msg("unable to get unread count of feed events")
-		return nil, status.Error(codes.Internal, "something went wrong")
-	}
-
 	list, err := s.service.FindByFilters(ctx, subscriberID, append(filters, unreadStateFilters...))
 	if err != nil {
 		log.Error().Err(err).Msg("unable to get user feed")
@@ -102,25 +94,38 @@ func (s *Server) GetUserFeed(ctx context.Context, req *inboxapi.GetUserFeedReque
 	return resp, nil
 }
 
-func (s *Server) calcCounters(ctx context.Context, subscriberID uuid.UUID) (totalCount int64, unreadCount int64, err error) {
-	filters := []Filter{
+// userFeedFilters returns the common feed filters ordered by the given filter and the read state filters separately,
+// because the unread counter has to be calculated without the read state filters.
+func userFeedFilters(order Filter, readState, archivedState inboxapi.GetUserFeedRequest_State) (filters []Filter, unreadStateFilters []Filter) {
+	filters = []Filter{
 		SkipSpammed(),
 		SkipCanceled(),
-		SortedByActuality(),
-		FilterByArchivedStatus(helpers.Ptr(false)),
+		order,
 	}
 
-	totalCount, err = s.service.CountByFilters(ctx, subscriberID, filters)
-	if err != nil {
-		return 0, 0, err
+	switch readState {
+	case inboxapi.GetUserFeedRequest_Exclude:
+		unreadStateFilters = append(unreadStateFilters, FilterByReadStatus(helpers.Ptr(false)))
+	case inboxapi.GetUserFeedRequest_ExcludeOther:
+		unreadStateFilters = append(unreadStateFilters, FilterByReadStatus(helpers.Ptr(true)))
+	default:
+		// GetUserFeedRequest_Include is default behaviour
 	}
 
-	unreadCount, err = s.service.CountByFilters(ctx, subscriberID, append(filters, FilterByReadStatus(helpers.Ptr(false))))
-	if err != nil {
-		return 0, 0, err
+	switch archivedState {
+	case inboxapi.GetUserFeedRequest_Exclude:
+		filters = append(filters, FilterByArchivedStatus(helpers.Ptr(false)))
+	case inboxapi.GetUserFeedRequest_ExcludeOther:
+		filters = append(filters, FilterByArchivedStatus(helpers.Ptr(true)))
+	default:
+		// GetUserFeedRequest_Include is default behaviour
 	}
 
-	return totalCount, unreadCount, nil
+	return filters, unreadStateFilters
+}
+
+func (s *Server) calcCounters(ctx context.Context, subscriberID uuid.UUID) (totalCount int64, unreadCount int64, err error) {
+	return s.service.Counters(ctx, subscriberID)
 }
 
 func (s *Server) MarkAsRead(ctx context.Context, req *inboxapi.MarkAsReadRequest) (*inboxapi.UnreadStats, error) {
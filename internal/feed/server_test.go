@@ -0,0 +1,77 @@
+package feed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetUserFeedCounts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	server := NewServer(NewService(store, nil, nil, nil))
+	subscriber := uuid.New()
+	items := storeTestItems(t, store, subscriber, 5)
+	require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID))
+	require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID, items[2].ID))
+
+	for name, tc := range map[string]struct {
+		readState, archivedState inboxapi.GetUserFeedRequest_State
+		total, unread            uint32
+	}{
+		"materialized counters": {
+			readState:     inboxapi.GetUserFeedRequest_Include,
+			archivedState: inboxapi.GetUserFeedRequest_Exclude,
+			total:         3,
+			unread:        2,
+		},
+		"materialized unread counters": {
+			readState:     inboxapi.GetUserFeedRequest_Exclude,
+			archivedState: inboxapi.GetUserFeedRequest_Exclude,
+			total:         2,
+			unread:        2,
+		},
+		"counted archived items": {
+			readState:     inboxapi.GetUserFeedRequest_Include,
+			archivedState: inboxapi.GetUserFeedRequest_ExcludeOther,
+			total:         2,
+			unread:        1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			list, err := server.GetUserFeed(ctx, &inboxapi.GetUserFeedRequest{
+				SubscriberId:  subscriber.String(),
+				ReadState:     tc.readState,
+				ArchivedState: tc.archivedState,
+				Limit:         1,
+				Offset:        1,
+			})
+			require.NoError(t, err)
+
+			assert.Len(t, list.GetList(), 1)
+			assert.Equal(t, tc.total, list.GetTotalCount())
+			assert.Equal(t, tc.unread, list.GetUnreadCount(), "unread items of all pages are counted")
+		})
+	}
+}
+
+func TestServer_GetUserFeedMaxLimit(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	server := NewServer(NewService(store, nil, nil, nil))
+	subscriber := uuid.New()
+	storeTestItems(t, store, subscriber, maxPageLimit+1)
+
+	list, err := server.GetUserFeed(ctx, &inboxapi.GetUserFeedRequest{
+		SubscriberId: subscriber.String(),
+		Limit:        maxPageLimit * 2,
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, list.GetList(), maxPageLimit)
+	assert.Equal(t, uint32(maxPageLimit+1), list.GetTotalCount())
+}
@@ -5,11 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/google/uuid"
-	coresdk "github.com/goverland-labs/goverland-core-sdk-go"
 	"github.com/goverland-labs/goverland-core-sdk-go/feed"
 	"github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
 	"github.com/rs/zerolog/log"
@@ -20,10 +18,6 @@ import (
 	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
 )
 
-const (
-	maxPrefillElements = 200
-)
-
 type SubscriptionsFinder interface {
 	FindSubscribers(ctx context.Context, in *inboxapi.FindSubscribersRequest, opts ...grpc.CallOption) (*inboxapi.UserList, error)
 	ListSubscriptions(ctx context.Context, in *inboxapi.ListSubscriptionRequest, opts ...grpc.CallOption) (*inboxapi.ListSubscriptionResponse, error)
@@ -33,125 +27,113 @@ type SettingsProvider interface {
 	GetFeedSettings(ctx context.Context, in *inboxapi.GetFeedSettingsRequest, opts ...grpc.CallOption) (*inboxapi.GetFeedSettingsResponse, error)
 }
 
+// FeedStore is the storage of feed items and settings. Repo is the production implementation,
+// MemoryStore is used in tests.
+type FeedStore interface {
+	CreateOrUpdate(ctx context.Context, item *Item) error
+	BulkCreateOrUpdate(ctx context.Context, items []Item) error
+	FindSubscribersByProposalID(ctx context.Context, proposalID string) ([]uuid.UUID, error)
+	FindSubscribersByDiscussionID(ctx context.Context, discussionID string) ([]uuid.UUID, error)
+	FindSubscribersByDaoItem(ctx context.Context, daoID uuid.UUID) ([]uuid.UUID, error)
+	MarkAsReadByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error
+	MarkAsUnreadByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error
+	MarkAsReadByTime(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error
+	MarkAsUnreadByTime(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error
+	MarkAsArchivedByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, reason ArchiveReason, id ...uuid.UUID) error
+	MarkAsUnarchivedByID(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, id ...uuid.UUID) error
+	MarkAsArchivedByTime(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, t time.Time) error
+	MarkAsVoted(ctx context.Context, subscriberID uuid.UUID, proposalID string) error
+	Snooze(ctx context.Context, subscriberID uuid.UUID, snooze Snooze, id ...uuid.UUID) error
+	Unsnooze(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error
+	WakeSnoozed(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+	Unsubscribe(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, daoID uuid.UUID, mode UnsubscribeMode) error
+	Unfreeze(ctx context.Context, subscriberID uuid.UUID, daoID ...uuid.UUID) error
+	CountByFilters(ctx context.Context, filters []Filter) (int64, error)
+	CountByDao(ctx context.Context, filters []Filter) ([]DaoCounters, error)
+	GetCounters(ctx context.Context, subscriberID uuid.UUID) (Counters, error)
+	FindByFilters(ctx context.Context, filters []Filter) ([]Item, error)
+	AutoArchive(ctx context.Context, items []Item) ([]Item, error)
+	FindItemHistory(ctx context.Context, subscriberID, itemID uuid.UUID, before int64, limit int) ([]ItemHistoryEvent, error)
+	GetFeedSettings(ctx context.Context, subscriber uuid.UUID) (*Settings, error)
+	StoreSettings(ctx context.Context, sd *Settings) error
+	SetDaoEvents(ctx context.Context, subscriberID uuid.UUID, enabled bool) error
+	SetAutoArchiveRules(ctx context.Context, subscriberID uuid.UUID, rules AutoArchiveRules) error
+	FindSettings(ctx context.Context, subscriberIDs []uuid.UUID) ([]Settings, error)
+	FindDaoEventsSubscribers(ctx context.Context, subscriberIDs []uuid.UUID) ([]uuid.UUID, error)
+}
+
 type Service struct {
-	repo          *Repo
+	repo          FeedStore
 	subscriptions SubscriptionsFinder
 	settings      SettingsProvider
-	sdk           *coresdk.Client
+	backfiller    *Backfiller
+	fanout        *fanout
 }
 
-func NewService(repo *Repo, subscriptions SubscriptionsFinder, sp SettingsProvider, sdk *coresdk.Client) *Service {
+func NewService(repo FeedStore, subscriptions SubscriptionsFinder, sp SettingsProvider, backfiller *Backfiller) *Service {
 	return &Service{
 		repo:          repo,
 		subscriptions: subscriptions,
 		settings:      sp,
-		sdk:           sdk,
+		backfiller:    backfiller,
+		fanout:        newFanout(repo, subscriptions),
 	}
 }
 
-// Process feed item based on subscriber list.
+// Process feed item based on subscriber list, proposals and discussions are processed in the same way.
 // First of update proposal for already exists users and then try to add to the DAO subscribers.
+// DAO-level items are delivered only to subscribers which opted in to them.
 func (s *Service) Process(ctx context.Context, item Item) error {
-	// skip dao objects from feed
-	if item.DAO() {
-		return nil
-	}
-
-	processedSubscribers := make(map[uuid.UUID]struct{})
-	list, err := s.repo.FindByFilters(context.Background(), []Filter{
-		FilterByProposalID(item.ProposalID),
-	})
-	if err != nil {
-		return fmt.Errorf("find by filters: %w", err)
-	}
-
-	for i := range list {
-		personalized := item
-		personalized.SubscriberID = list[i].SubscriberID
-		if err = s.repo.CreateOrUpdate(&personalized); err != nil {
-			return fmt.Errorf("unable to save feed item '%s' for subscriber '%s': %w", personalized.ID, list[i].SubscriberID.String(), err)
-		}
-
-		processedSubscribers[list[i].SubscriberID] = struct{}{}
-	}
-
-	resp, err := s.subscriptions.FindSubscribers(ctx, &inboxapi.FindSubscribersRequest{
-		DaoId: item.DaoID.String(),
-	})
-	if err != nil {
-		return fmt.Errorf("find subscribers: %w", err)
-	}
-
-	var prInfo ShortProposalInfo
-	err = json.Unmarshal(item.Snapshot, &prInfo)
+	report, err := s.fanout.Run(ctx, item)
 	if err != nil {
-		return fmt.Errorf("unmarshal snapshot: %w", err)
+		return err
 	}
 
-	itemActive := prInfo.Active()
-	for _, sub := range resp.Users {
-		subscriberID, err := uuid.Parse(sub.GetUserId())
-		if err != nil {
-			return fmt.Errorf("unable to parse subscriber id '%s': %w", sub.GetUserId(), err)
-		}
-
-		// skip processed
-		if _, ok := processedSubscribers[subscriberID]; ok {
-			continue
-		}
-
-		// do not add item if it already closed
-		if !itemActive {
-			return nil
-		}
-
-		personalized := item
-		personalized.SubscriberID = subscriberID
-
-		if personalized.CreatedAt.IsZero() {
-			personalized.CreatedAt = time.Now()
-		}
-
-		if err := s.repo.CreateOrUpdate(&personalized); err != nil {
-			return fmt.Errorf("unable to save feed item '%s' for subscriber '%s': %w", personalized.ID, sub.GetUserId(), err)
-		}
-	}
+	log.Debug().
+		Str("feed_id", item.ID.String()).
+		Int("updated", report.Updated).
+		Bool("eligible", report.Eligible).
+		Int("created", report.Created).
+		Int("invalid", report.Invalid).
+		Msg("feed item processed")
 
 	return nil
 }
 
+// MarkAsReadByID marks items as read on request of the subscriber via the API, MarkAs* methods of the service record
+// changes with SourceGRPC.
 func (s *Service) MarkAsReadByID(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
-	return s.repo.MarkAsReadByID(ctx, subscriberID, id...)
+	return s.repo.MarkAsReadByID(ctx, subscriberID, SourceGRPC, id...)
 }
 
 func (s *Service) MarkAsUnreadByID(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
-	return s.repo.MarkAsUnreadByID(ctx, subscriberID, id...)
+	return s.repo.MarkAsUnreadByID(ctx, subscriberID, SourceGRPC, id...)
 }
 
 func (s *Service) MarkAsReadByTime(ctx context.Context, subscriberID uuid.UUID, t time.Time) error {
 	// dirty fix to mark as read without counting nanoseconds
 	t = t.Add(time.Second)
 
-	return s.repo.MarkAsReadByTime(ctx, subscriberID, t)
+	return s.repo.MarkAsReadByTime(ctx, subscriberID, SourceGRPC, t)
 }
 
 func (s *Service) MarkAsUnreadByTime(ctx context.Context, subscriberID uuid.UUID, t time.Time) error {
 	// dirty fix to mark as read without counting nanoseconds
 	t = t.Add(-time.Second)
 
-	return s.repo.MarkAsUnreadByTime(ctx, subscriberID, t)
+	return s.repo.MarkAsUnreadByTime(ctx, subscriberID, SourceGRPC, t)
 }
 
 func (s *Service) MarkAsArchivedByID(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
-	return s.repo.MarkAsArchivedByID(ctx, subscriberID, id...)
+	return s.repo.MarkAsArchivedByID(ctx, subscriberID, SourceGRPC, ArchivedManually, id...)
 }
 
 func (s *Service) MarkAsUnarchivedByID(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
-	return s.repo.MarkAsUnarchivedByID(ctx, subscriberID, id...)
+	return s.repo.MarkAsUnarchivedByID(ctx, subscriberID, SourceGRPC, id...)
 }
 
 func (s *Service) MarkAsArchivedByTime(ctx context.Context, subscriberID uuid.UUID, t time.Time) error {
-	return s.repo.MarkAsArchivedByTime(ctx, subscriberID, t)
+	return s.repo.MarkAsArchivedByTime(ctx, subscriberID, SourceGRPC, t)
 }
 
 func (s *Service) FindByFilters(ctx context.Context, subscriberID uuid.UUID, filters []Filter) ([]Item, error) {
@@ -193,54 +175,209 @@ func (s *Service) CountByFilters(ctx context.Context, subscriberID uuid.UUID, fi
 	return found, nil
 }
 
-func (s *Service) Subscribe(ctx context.Context, subscriberID, daoID uuid.UUID) error {
-	df, err := s.getDaoFeed(ctx, daoID)
+// countersFilters returns filters of items which are taken into account in the feed counters.
+func countersFilters() []Filter {
+	return append(countedFilters(), FilterByArchivedStatus(helpers.Ptr(false)))
+}
+
+// Counters returns the number of actual and unread actual items in the subscriber feed.
+// They are read from materialized counters.
+func (s *Service) Counters(ctx context.Context, subscriberID uuid.UUID) (totalCount int64, unreadCount int64, err error) {
+	counters, err := s.GetCounters(ctx, subscriberID)
 	if err != nil {
-		return fmt.Errorf("getDaoFeed: %w", err)
+		return 0, 0, err
 	}
-	if df == nil {
-		return nil
+
+	return counters.TotalCount, counters.UnreadCount, nil
+}
+
+// GetCounters returns materialized counters of the subscriber feed.
+func (s *Service) GetCounters(ctx context.Context, subscriberID uuid.UUID) (Counters, error) {
+	counters, err := s.repo.GetCounters(ctx, subscriberID)
+	if err != nil {
+		return Counters{}, fmt.Errorf("get counters: %w", err)
 	}
 
-	subscriberFeed := append([]feed.Item{}, df.Items...)
-	sort.Slice(subscriberFeed, func(i, j int) bool {
-		return subscriberFeed[i].CreatedAt.After(subscriberFeed[j].CreatedAt)
-	})
+	return counters, nil
+}
 
-	for _, item := range subscriberFeed {
-		if err := s.repo.CreateOrUpdate(convertCoreFeedItemToInternal(subscriberID, item)); err != nil {
-			log.Error().Err(err).Str("feed_id", item.ID.String()).Msg("unable to save feed")
+// CountersByDao returns the same counters as Counters does grouped by dao, daos without items are omitted.
+func (s *Service) CountersByDao(ctx context.Context, subscriberID uuid.UUID) ([]DaoCounters, error) {
+	filters := append(countersFilters(), FilterBySubscriberID(subscriberID))
 
-			continue
-		}
+	list, err := s.repo.CountByDao(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("count by dao: %w", err)
+	}
+
+	return list, nil
+}
+
+// Subscribe schedules the backfill of the subscriber feed with active proposals of the dao.
+func (s *Service) Subscribe(ctx context.Context, subscriberID, daoID uuid.UUID) error {
+	// items frozen when the subscriber left the dao are updated again
+	if err := s.repo.Unfreeze(ctx, subscriberID, daoID); err != nil {
+		return fmt.Errorf("unfreeze: %w", err)
+	}
+
+	if _, err := s.backfiller.Enqueue(ctx, subscriberID, daoID); err != nil {
+		return fmt.Errorf("enqueue backfill: %w", err)
 	}
 
 	return nil
 }
 
-func (s *Service) getDaoFeed(ctx context.Context, daoID uuid.UUID) (*feed.Feed, error) {
-	daoFeed, err := s.sdk.GetFeedByFilters(ctx, coresdk.FeedByFiltersRequest{
-		IsActive: helpers.Ptr(true),
-		DaoList:  []string{daoID.String()},
-		Types:    []string{"proposal"},
-		Limit:    maxPrefillElements,
-	})
+// BulkSubscribe fills the subscriber feed with active proposals of many daos at once and returns backfill jobs of them.
+func (s *Service) BulkSubscribe(ctx context.Context, subscriberID uuid.UUID, daoIDs []uuid.UUID) ([]BackfillJob, error) {
+	if err := s.repo.Unfreeze(ctx, subscriberID, daoIDs...); err != nil {
+		return nil, fmt.Errorf("unfreeze: %w", err)
+	}
+
+	jobs, err := s.backfiller.BackfillMany(ctx, subscriberID, daoIDs)
 	if err != nil {
-		return nil, fmt.Errorf("get feed by filters: %w", err)
+		return nil, fmt.Errorf("backfill many: %w", err)
 	}
 
-	return daoFeed, nil
+	return jobs, nil
 }
 
-func (s *Service) markExpiredAsAutoArchived(ctx context.Context) error {
-	err := s.repo.AutoArchive(ctx)
+// BackfillStatus returns backfill jobs of the subscriber, uuid.Nil dao id means all daos.
+func (s *Service) BackfillStatus(ctx context.Context, subscriberID, daoID uuid.UUID) ([]BackfillJob, error) {
+	jobs, err := s.backfiller.Find(ctx, subscriberID, daoID)
 	if err != nil {
-		return fmt.Errorf("s.repo.AutoArchive: %w", err)
+		return nil, fmt.Errorf("find backfills: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Unsubscribe stops updates of dao items in the subscriber feed, the mode defines what happens to existing items.
+func (s *Service) Unsubscribe(ctx context.Context, subscriberID uuid.UUID, source TransitionSource, daoID uuid.UUID, mode UnsubscribeMode) error {
+	if err := s.repo.Unsubscribe(ctx, subscriberID, source, daoID, mode); err != nil {
+		return fmt.Errorf("unsubscribe: %w", err)
+	}
+
+	return nil
+}
+
+// Snooze hides items until the time or the milestone action defined by the snooze.
+func (s *Service) Snooze(ctx context.Context, subscriberID uuid.UUID, snooze Snooze, id ...uuid.UUID) error {
+	if err := snooze.Validate(time.Now()); err != nil {
+		return err
+	}
+
+	return s.repo.Snooze(ctx, subscriberID, snooze, id...)
+}
+
+func (s *Service) Unsnooze(ctx context.Context, subscriberID uuid.UUID, id ...uuid.UUID) error {
+	return s.repo.Unsnooze(ctx, subscriberID, id...)
+}
+
+func (s *Service) wakeSnoozed(ctx context.Context) error {
+	if _, err := s.repo.WakeSnoozed(ctx, time.Now()); err != nil {
+		return fmt.Errorf("s.repo.WakeSnoozed: %w", err)
 	}
 
 	return nil
 }
 
+// markExpiredAsAutoArchived walks through not archived proposals which could be matched by auto-archive rules
+// by batches in the primary key order and archives ones matched by rules of their subscribers.
+// Returns the number of archived items.
+func (s *Service) markExpiredAsAutoArchived(ctx context.Context, batchSize int) (int, error) {
+	var (
+		after    *Item
+		archived int
+		now      = time.Now()
+	)
+
+	for {
+		filters := []Filter{
+			FilterByArchivedStatus(helpers.Ptr(false)),
+			FilterByType(Proposal),
+			FilterAutoArchiveCandidates(now),
+			WithAutoArchiveFields(),
+			SortedByPrimaryKey(),
+			WithLimit(batchSize, 0),
+		}
+		if after != nil {
+			filters = append(filters, FilterAfterPrimaryKey(after.ID, after.SubscriberID))
+		}
+
+		batch, err := s.repo.FindByFilters(ctx, filters)
+		if err != nil {
+			return archived, fmt.Errorf("s.repo.FindByFilters: %w", err)
+		}
+
+		count, err := s.autoArchiveBatch(ctx, batch)
+		archived += count
+		if err != nil {
+			return archived, err
+		}
+
+		if len(batch) < batchSize {
+			return archived, nil
+		}
+
+		after = &batch[len(batch)-1]
+	}
+}
+
+func (s *Service) autoArchiveBatch(ctx context.Context, batch []Item) (int, error) {
+	rules, err := s.autoArchiveRules(ctx, batch)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var expired []Item
+	for i := range batch {
+		if rules[batch[i].SubscriberID].Match(&batch[i], now) {
+			expired = append(expired, batch[i])
+		}
+	}
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	archived, err := s.repo.AutoArchive(ctx, expired)
+	if err != nil {
+		return 0, fmt.Errorf("s.repo.AutoArchive: %w", err)
+	}
+
+	return len(archived), nil
+}
+
+// autoArchiveRules returns effective auto-archive rules of subscribers of the items.
+func (s *Service) autoArchiveRules(ctx context.Context, items []Item) (map[uuid.UUID]AutoArchiveRules, error) {
+	rules := make(map[uuid.UUID]AutoArchiveRules)
+	var subscribers []uuid.UUID
+	for _, item := range items {
+		if _, ok := rules[item.SubscriberID]; ok {
+			continue
+		}
+
+		rules[item.SubscriberID] = defaultSettings(item.SubscriberID).EffectiveAutoarchiveRules()
+		subscribers = append(subscribers, item.SubscriberID)
+	}
+
+	if len(subscribers) == 0 {
+		return rules, nil
+	}
+
+	list, err := s.repo.FindSettings(ctx, subscribers)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.FindSettings: %w", err)
+	}
+
+	for _, set := range list {
+		rules[set.SubscriberID] = set.EffectiveAutoarchiveRules()
+	}
+
+	return rules, nil
+}
+
 func convertCoreFeedItemToInternal(subscriberID uuid.UUID, item feed.Item) *Item {
 	var timeline Timeline
 	err := json.Unmarshal(item.Timeline, &timeline)
@@ -263,6 +400,15 @@ func convertCoreFeedItemToInternal(subscriberID uuid.UUID, item feed.Item) *Item
 	}
 }
 
+// MarkAsVoted remembers the vote of the user, so updates don't unarchive the proposal.
+func (s *Service) MarkAsVoted(ctx context.Context, userID uuid.UUID, proposalID string) error {
+	if err := s.repo.MarkAsVoted(ctx, userID, proposalID); err != nil {
+		return fmt.Errorf("mark as voted: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) TryAutoarchive(ctx context.Context, userID uuid.UUID, proposalID string) error {
 	set, err := s.settings.GetFeedSettings(ctx, &inboxapi.GetFeedSettingsRequest{
 		UserId: userID.String(),
@@ -295,17 +441,62 @@ func (s *Service) TryAutoarchive(ctx context.Context, userID uuid.UUID, proposal
 		log.Warn().Msgf("find [%s] few feed item by proposal id %s", userID, proposalID)
 	}
 
-	if err = s.repo.MarkAsReadByID(ctx, userID, items[0].ID); err != nil {
+	if err = s.repo.MarkAsReadByID(ctx, userID, SourceVoteConsumer, items[0].ID); err != nil {
 		return fmt.Errorf("mark as read: %w", err)
 	}
 
-	if err = s.repo.MarkAsArchivedByID(ctx, userID, items[0].ID); err != nil {
+	if err = s.repo.MarkAsArchivedByID(ctx, userID, SourceVoteConsumer, ArchivedVoted, items[0].ID); err != nil {
 		return fmt.Errorf("mark as archived: %w", err)
 	}
 
 	return nil
 }
 
+// FeedSettings returns settings of the subscriber feed, default ones if they aren't stored yet.
+func (s *Service) FeedSettings(ctx context.Context, subscriberID uuid.UUID) (Settings, error) {
+	set, err := s.repo.GetFeedSettings(ctx, subscriberID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultSettings(subscriberID), nil
+	}
+	if err != nil {
+		return Settings{}, fmt.Errorf("get feed settings: %w", err)
+	}
+
+	return *set, nil
+}
+
+// SetDaoEvents opts the subscriber in to DAO-level items or out of them, existing DAO-level items are removed on opt-out.
+func (s *Service) SetDaoEvents(ctx context.Context, subscriberID uuid.UUID, enabled bool) error {
+	if err := s.repo.SetDaoEvents(ctx, subscriberID, enabled); err != nil {
+		return fmt.Errorf("set dao events: %w", err)
+	}
+
+	return nil
+}
+
+// SetAutoArchiveRules replaces auto-archive rules of the subscriber, empty rules restore the default one.
+func (s *Service) SetAutoArchiveRules(ctx context.Context, subscriberID uuid.UUID, rules AutoArchiveRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetAutoArchiveRules(ctx, subscriberID, rules); err != nil {
+		return fmt.Errorf("set auto-archive rules: %w", err)
+	}
+
+	return nil
+}
+
+// ItemHistory returns up to limit transitions of the subscriber items from the newest to the oldest, see Repo.FindItemHistory.
+func (s *Service) ItemHistory(ctx context.Context, subscriberID, itemID uuid.UUID, before int64, limit int) ([]ItemHistoryEvent, error) {
+	list, err := s.repo.FindItemHistory(ctx, subscriberID, itemID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find item history: %w", err)
+	}
+
+	return list, nil
+}
+
 func (s *Service) SaveSettings(ctx context.Context, subscriber uuid.UUID, autoarchiveAfterDays int) error {
 	set, err := s.repo.GetFeedSettings(ctx, subscriber)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -0,0 +1,28 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidSnooze = errors.New("invalid snooze")
+
+// Snooze defines when the snoozed item wakes up: at the time or when the action appears in its timeline,
+// whichever comes first. At least one of them has to be set.
+type Snooze struct {
+	Until       *time.Time
+	UntilAction Action
+}
+
+func (s Snooze) Validate(now time.Time) error {
+	if s.Until == nil && s.UntilAction == "" {
+		return fmt.Errorf("%w: neither time nor action is set", ErrInvalidSnooze)
+	}
+
+	if s.Until != nil && !s.Until.After(now) {
+		return fmt.Errorf("%w: time in the past", ErrInvalidSnooze)
+	}
+
+	return nil
+}
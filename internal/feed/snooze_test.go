@@ -0,0 +1,32 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnooze_Validate(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	for name, tc := range map[string]struct {
+		snooze Snooze
+		valid  bool
+	}{
+		"time":             {snooze: Snooze{Until: &future}, valid: true},
+		"action":           {snooze: Snooze{UntilAction: ProposalVotingEndsSoon}, valid: true},
+		"time and action":  {snooze: Snooze{Until: &future, UntilAction: ProposalVotingEndsSoon}, valid: true},
+		"time in the past": {snooze: Snooze{Until: &past}},
+		"empty":            {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.snooze.Validate(now)
+			assert.Equal(t, tc.valid, err == nil)
+			if !tc.valid {
+				assert.ErrorIs(t, err, ErrInvalidSnooze)
+			}
+		})
+	}
+}
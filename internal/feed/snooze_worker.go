@@ -0,0 +1,40 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	snoozeCheckDelay = time.Minute
+)
+
+type SnoozeWorker struct {
+	service *Service
+}
+
+func NewSnoozeWorker(s *Service) *SnoozeWorker {
+	return &SnoozeWorker{
+		service: s,
+	}
+}
+
+func (w *SnoozeWorker) Start(ctx context.Context) error {
+	for {
+		start := time.Now()
+		err := w.service.wakeSnoozed(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("wake snoozed feed items")
+		}
+
+		log.Debug().Msgf("wake snoozed feed items completed: %v", time.Since(start))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(snoozeCheckDelay):
+		}
+	}
+}
@@ -0,0 +1,1432 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/migrations"
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+)
+
+// testPostgresDSNEnv points to the database used by Repo tests, all data in it will be removed.
+const testPostgresDSNEnv = "FEED_TEST_POSTGRES_DSN"
+
+// ciEnv is set by CI runners, Repo tests are mandatory there
+const ciEnv = "CI"
+
+// testUpdatePolicy resurfaces and unarchives items in conformance tests.
+var testUpdatePolicy = UpdatePolicy{
+	Resurface: ResurfacePolicy{
+		Actions: []Action{ProposalVotingQuorumReached},
+		States:  []string{ProposalStateSucceeded},
+	},
+	Archived: ArchivedPolicy{
+		Mode:             ArchivedUpdateFreeze,
+		UnarchiveActions: []Action{ProposalVotingEndsSoon},
+	},
+}
+
+// conformanceStore is implemented by every FeedStore which stores events in the outbox.
+type conformanceStore interface {
+	FeedStore
+	OutboxStore
+	CountersStore
+	BackfillStore
+	Scheduler
+}
+
+func TestMemoryStore(t *testing.T) {
+	testFeedStore(t, func(t *testing.T) conformanceStore {
+		return NewMemoryStore(testUpdatePolicy)
+	})
+}
+
+func TestRepo(t *testing.T) {
+	dsn := os.Getenv(testPostgresDSNEnv)
+	if dsn == "" && os.Getenv(ciEnv) != "" {
+		t.Fatalf("%s is required in CI", testPostgresDSNEnv)
+	}
+	if dsn == "" {
+		t.Skipf("%s is not set", testPostgresDSNEnv)
+	}
+
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+
+	db, err := conn.DB()
+	require.NoError(t, err)
+
+	m, err := migrations.NewMigrator(db)
+	require.NoError(t, err)
+
+	_, err = m.Up(context.Background())
+	require.NoError(t, err)
+
+	testFeedStore(t, func(t *testing.T) conformanceStore {
+		require.NoError(t, conn.Exec("truncate items, settings, outbox, counters, backfill_jobs, item_events, job_runs").Error)
+
+		return NewRepo(conn, testUpdatePolicy)
+	})
+}
+
+// testFeedStore is the conformance suite, every FeedStore implementation must pass it.
+func testFeedStore(t *testing.T, newStore func(t *testing.T) conformanceStore) {
+	ctx := context.Background()
+
+	t.Run("create or update", func(t *testing.T) {
+		store := newStore(t)
+		item := storeTestItem(uuid.New(), "proposal", ProposalStateActive, 1)
+
+		require.NoError(t, store.CreateOrUpdate(ctx, &item))
+		require.NoError(t, store.MarkAsReadByID(ctx, item.SubscriberID, SourceGRPC, item.ID))
+
+		updated := item
+		updated.ID = uuid.New()
+		updated.Action = ProposalVotingEnded
+		updated.Snapshot = storeTestSnapshot(ProposalStateFailed, 1)
+		updated.Timeline = Timeline{{CreatedAt: item.CreatedAt, Action: ProposalVotingEnded}}
+		require.NoError(t, store.CreateOrUpdate(ctx, &updated))
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(item.SubscriberID)})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, item.ID, list[0].ID)
+		assert.Equal(t, ProposalVotingEnded, list[0].Action)
+		assert.JSONEq(t, string(updated.Snapshot), string(list[0].Snapshot))
+		assert.True(t, updated.Timeline.Equal(list[0].Timeline))
+		assert.NotNil(t, list[0].ReadAt, "not significant update keeps the read state")
+		assert.Empty(t, list[0].ResurfacedReason)
+	})
+
+	t.Run("resurface on significant update", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID, items[2].ID))
+		publishOutbox(t, store)
+
+		quorum := items[0]
+		quorum.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingQuorumReached}}
+		succeeded := items[1]
+		succeeded.Snapshot = storeTestSnapshot(ProposalStateSucceeded, 1)
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{quorum, succeeded}))
+
+		insignificant := items[2]
+		insignificant.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingEnded}}
+		require.NoError(t, store.CreateOrUpdate(ctx, &insignificant))
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber), FilterByReadStatus(helpers.Ptr(false))})
+		require.NoError(t, err)
+		reasons := make(map[string]string)
+		for _, item := range list {
+			assert.NotNil(t, item.ResurfacedAt)
+			reasons[item.ProposalID] = item.ResurfacedReason
+		}
+		assert.Equal(t, map[string]string{
+			quorum.ProposalID:    string(ProposalVotingQuorumReached),
+			succeeded.ProposalID: "state:" + ProposalStateSucceeded,
+		}, reasons)
+		assertCounters(t, store, subscriber)
+
+		assert.Equal(t, []string{SubjectItemUpdated, SubjectItemUpdated, SubjectItemResurfaced, SubjectItemResurfaced, SubjectItemUpdated}, publishOutbox(t, store))
+
+		// the same update isn't significant anymore
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID))
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{quorum}))
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), FilterByReadStatus(helpers.Ptr(false)))
+	})
+
+	t.Run("archived items on update", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 4)
+		ids := []uuid.UUID{items[0].ID, items[1].ID, items[2].ID, items[3].ID}
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, ids...))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, ids...))
+		require.NoError(t, store.MarkAsVoted(ctx, subscriber, items[2].ProposalID))
+		publishOutbox(t, store)
+
+		frozen := items[0]
+		frozen.Snapshot = storeTestSnapshot(ProposalStateSucceeded, 1)
+		require.NoError(t, store.CreateOrUpdate(ctx, &frozen))
+		assert.NotNil(t, frozen.ArchivedAt)
+
+		endsSoon := items[1]
+		endsSoon.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingEndsSoon}}
+		voted := items[2]
+		voted.Timeline = endsSoon.Timeline
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{endsSoon, voted}))
+		require.NoError(t, store.MarkAsUnarchivedByID(ctx, subscriber, SourceGRPC, items[3].ID))
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber)})
+		require.NoError(t, err)
+		stored := make(map[string]Item)
+		for _, item := range list {
+			stored[item.ProposalID] = item
+		}
+
+		state, _ := snapshotText(helpers.Ptr(stored[frozen.ProposalID]), "state")
+		assert.Equal(t, ProposalStateActive, state)
+		assert.NotNil(t, stored[frozen.ProposalID].ArchivedAt)
+
+		assert.Nil(t, stored[endsSoon.ProposalID].ArchivedAt)
+		assert.Nil(t, stored[endsSoon.ProposalID].ReadAt)
+		assert.Equal(t, string(ProposalVotingEndsSoon), stored[endsSoon.ProposalID].UnarchivedReason)
+		assert.Equal(t, string(ProposalVotingEndsSoon), stored[endsSoon.ProposalID].ResurfacedReason)
+
+		assert.NotNil(t, stored[voted.ProposalID].ArchivedAt)
+		assert.NotNil(t, stored[voted.ProposalID].VotedAt)
+
+		assert.Nil(t, stored[items[3].ProposalID].ArchivedAt)
+		assert.Equal(t, UnarchivedManually, stored[items[3].ProposalID].UnarchivedReason)
+		assertCounters(t, store, subscriber)
+
+		assert.Equal(t, []string{SubjectItemUpdated, SubjectItemUnarchived, SubjectItemResurfaced, SubjectItemUnarchived}, publishOutbox(t, store))
+	})
+
+	t.Run("concurrent process of the same proposal", func(t *testing.T) {
+		store := newStore(t)
+		item := storeTestItem(uuid.Nil, "proposal", ProposalStateActive, 1)
+		subscriptions := &fakeSubscriptions{subscribers: map[string][]string{}}
+		subscribers := make([]uuid.UUID, 0, 10)
+		for range 10 {
+			subscriber := uuid.New()
+			subscribers = append(subscribers, subscriber)
+			subscriptions.subscribers[item.DaoID.String()] = append(subscriptions.subscribers[item.DaoID.String()], subscriber.String())
+		}
+		service := NewService(store, subscriptions, nil, nil)
+
+		const workers = 8
+		sent := make(map[int64]bool, workers)
+		var wg sync.WaitGroup
+		errs := make(chan error, workers)
+		for i := range workers {
+			update := item
+			update.Timeline = Timeline{{CreatedAt: time.Now().Add(time.Duration(i) * time.Minute), Action: ProposalUpdated}}
+			sent[update.Timeline[0].CreatedAt.UnixNano()] = true
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				errs <- service.Process(ctx, update)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		assertCount(t, store, int64(len(subscribers)), FilterByProposalID(item.ProposalID))
+		for _, subscriber := range subscribers {
+			assertCounters(t, store, subscriber)
+		}
+
+		// updates aren't lost or mixed: every subscriber has the timeline of the same single update
+		var last int64
+		for _, subscriber := range subscribers {
+			list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber), FilterByProposalID(item.ProposalID)})
+			require.NoError(t, err)
+			require.Len(t, list, 1)
+			require.Len(t, list[0].Timeline, 1)
+
+			got := list[0].Timeline[0].CreatedAt.UnixNano()
+			assert.True(t, sent[got], "timeline of the unknown update")
+			if last != 0 {
+				assert.Equal(t, last, got, "subscribers have different updates")
+			}
+			last = got
+		}
+
+		// every write is stored: the item is created once per subscriber and updated by the rest of workers
+		events := make(map[string]int)
+		for _, subject := range publishOutbox(t, store) {
+			events[subject]++
+		}
+		assert.Equal(t, map[string]int{
+			SubjectItemCreated: len(subscribers),
+			SubjectItemUpdated: (workers - 1) * len(subscribers),
+		}, events)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		for mode, tc := range map[UnsubscribeMode]struct {
+			count    int64
+			archived int64
+			events   []string
+			// resumed is the action of the item updated after the subscriber came back
+			resumed Action
+		}{
+			UnsubscribePurge: {count: 0, events: []string{SubjectItemDeleted, SubjectItemDeleted}},
+			// archived items follow the archived update mode
+			UnsubscribeArchive: {count: 2, archived: 2, events: []string{SubjectItemArchived, SubjectItemArchived}, resumed: ProposalCreated},
+			UnsubscribeFreeze:  {count: 2, resumed: ProposalVotingEnded},
+		} {
+			t.Run(string(mode), func(t *testing.T) {
+				store := newStore(t)
+				subscriber := uuid.New()
+				items := storeTestItems(t, store, subscriber, 2)
+				other := storeTestItem(subscriber, "other", ProposalStateActive, 1)
+				other.DaoID = uuid.NameSpaceURL
+				require.NoError(t, store.CreateOrUpdate(ctx, &other))
+				publishOutbox(t, store)
+
+				require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceGRPC, items[0].DaoID, mode))
+				assert.Equal(t, tc.events, publishOutbox(t, store))
+				assertCount(t, store, tc.count, FilterBySubscriberID(subscriber), FilterByDaoIDs(items[0].DaoID))
+				assertCount(t, store, tc.archived, FilterBySubscriberID(subscriber), FilterByArchivedStatus(helpers.Ptr(true)))
+				assertCounters(t, store, subscriber)
+
+				subscribers, err := store.FindSubscribersByProposalID(ctx, items[0].ProposalID)
+				require.NoError(t, err)
+				assert.Empty(t, subscribers)
+				subscribers, err = store.FindSubscribersByProposalID(ctx, other.ProposalID)
+				require.NoError(t, err)
+				assert.Equal(t, []uuid.UUID{subscriber}, subscribers)
+				if mode == UnsubscribePurge {
+					return
+				}
+
+				// frozen items aren't updated until the subscriber comes back
+				updated := items[0]
+				updated.Action = ProposalVotingEnded
+				require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{updated}))
+				assert.Equal(t, map[uuid.UUID]Action{subscriber: ProposalCreated}, proposalActions(t, store, updated.ProposalID))
+				assert.Empty(t, publishOutbox(t, store))
+
+				require.NoError(t, store.Unfreeze(ctx, subscriber, items[0].DaoID))
+				require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{updated}))
+				assert.Equal(t, map[uuid.UUID]Action{subscriber: tc.resumed}, proposalActions(t, store, updated.ProposalID))
+			})
+		}
+	})
+
+	t.Run("backfill jobs", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, dao := uuid.New(), uuid.New()
+
+		job, err := store.EnqueueBackfill(ctx, subscriber, dao)
+		require.NoError(t, err)
+		assert.Equal(t, BackfillPending, job.Status)
+
+		claimed, err := store.ClaimBackfills(ctx, 10, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, BackfillRunning, claimed[0].Status)
+		stalled := claimed[0]
+
+		// the running job is neither claimed twice nor restarted
+		claimed, err = store.ClaimBackfills(ctx, 10, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+		job, err = store.EnqueueBackfill(ctx, subscriber, dao)
+		require.NoError(t, err)
+		assert.Equal(t, BackfillRunning, job.Status)
+
+		// the stalled job is claimed again, its previous worker can't save it anymore
+		claimed, err = store.ClaimBackfills(ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		stalled.Processed = 50
+		assert.ErrorIs(t, store.SaveBackfill(ctx, &stalled), ErrBackfillNotRunning)
+
+		job = claimed[0]
+		job.Status = BackfillPending
+		job.Processed = 100
+		job.Attempts = 1
+		job.NextRunAt = time.Now().Add(time.Hour)
+		require.NoError(t, store.SaveBackfill(ctx, &job))
+
+		// the retry isn't claimed before its time
+		claimed, err = store.ClaimBackfills(ctx, 10, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		jobs, err := store.FindBackfills(ctx, subscriber, uuid.Nil)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, 100, jobs[0].Processed)
+		assert.Equal(t, 1, jobs[0].Attempts)
+
+		jobs, err = store.FindBackfills(ctx, subscriber, uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, jobs)
+
+		// the finished job starts from the beginning
+		job.Status = BackfillFailed
+		require.NoError(t, store.ReplaceBackfill(ctx, &job))
+		job, err = store.EnqueueBackfill(ctx, subscriber, dao)
+		require.NoError(t, err)
+		assert.Equal(t, BackfillPending, job.Status)
+		assert.Zero(t, job.Processed)
+		assert.Zero(t, job.Attempts)
+	})
+
+	t.Run("unsubscribe cancels backfills", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, running, pending := uuid.New(), uuid.New(), uuid.New()
+
+		_, err := store.EnqueueBackfill(ctx, subscriber, running)
+		require.NoError(t, err)
+		claimed, err := store.ClaimBackfills(ctx, 10, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		_, err = store.EnqueueBackfill(ctx, subscriber, pending)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceGRPC, running, UnsubscribeFreeze))
+		require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceGRPC, pending, UnsubscribePurge))
+
+		// the worker of the canceled job can't store its progress
+		canceled := claimed[0]
+		canceled.Processed = 10
+		assert.ErrorIs(t, store.SaveBackfill(ctx, &canceled), ErrBackfillNotRunning)
+
+		jobs, err := store.FindBackfills(ctx, subscriber, uuid.Nil)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		for _, job := range jobs {
+			assert.Equal(t, BackfillCanceled, job.Status)
+			assert.NotNil(t, job.FinishedAt)
+			assert.Zero(t, job.Processed)
+		}
+
+		claimed, err = store.ClaimBackfills(ctx, 10, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, claimed)
+
+		// the next subscribe starts the canceled job again, the worker of the canceled run can't save it
+		job, err := store.EnqueueBackfill(ctx, subscriber, running)
+		require.NoError(t, err)
+		assert.Equal(t, BackfillPending, job.Status)
+		restarted, err := store.ClaimBackfills(ctx, 10, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.Len(t, restarted, 1)
+		assert.ErrorIs(t, store.SaveBackfill(ctx, &canceled), ErrBackfillNotRunning)
+		require.NoError(t, store.SaveBackfill(ctx, &restarted[0]))
+	})
+
+	t.Run("bulk create or update", func(t *testing.T) {
+		store := newStore(t)
+		first, second := uuid.New(), uuid.New()
+
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{
+			storeTestItem(first, "proposal", ProposalStateActive, 1),
+			storeTestItem(second, "proposal", ProposalStateActive, 1),
+			storeTestItem(second, "other", ProposalStateActive, 1),
+		}))
+
+		updated := storeTestItem(first, "proposal", ProposalStateActive, 1)
+		updated.Action = ProposalVotingQuorumReached
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{updated}))
+
+		subscribers, err := store.FindSubscribersByProposalID(ctx, "proposal")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{first, second}, subscribers)
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(first)})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, ProposalVotingQuorumReached, list[0].Action)
+	})
+
+	t.Run("read state by id", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+		foreign := storeTestItems(t, store, uuid.New(), 1)
+
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID, foreign[0].ID))
+		assertCount(t, store, 2, FilterBySubscriberID(subscriber), FilterByReadStatus(helpers.Ptr(true)))
+		assertCount(t, store, 2, FilterByReadStatus(helpers.Ptr(false)))
+
+		require.NoError(t, store.MarkAsUnreadByID(ctx, subscriber, SourceGRPC, items[1].ID))
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), FilterByReadStatus(helpers.Ptr(true)))
+	})
+
+	t.Run("read state by time", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		storeTestItems(t, store, subscriber, 2)
+
+		require.NoError(t, store.MarkAsReadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-2*time.Hour)))
+		assertCount(t, store, 0, FilterByReadStatus(helpers.Ptr(true)))
+
+		require.NoError(t, store.MarkAsReadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(time.Minute)))
+		assertCount(t, store, 2, FilterByReadStatus(helpers.Ptr(true)))
+
+		require.NoError(t, store.MarkAsUnreadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(time.Minute)))
+		assertCount(t, store, 2, FilterByReadStatus(helpers.Ptr(true)))
+
+		require.NoError(t, store.MarkAsUnreadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-2*time.Hour)))
+		assertCount(t, store, 0, FilterByReadStatus(helpers.Ptr(true)))
+	})
+
+	// regression: unread by time used to set read_at to the current time, so items stayed read
+	t.Run("unread by time clears read time", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 2)
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID))
+
+		require.NoError(t, store.MarkAsUnreadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-2*time.Hour)))
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber)})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		for _, item := range list {
+			assert.Nil(t, item.ReadAt, item.ProposalID)
+		}
+	})
+
+	t.Run("archived state", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[0].ID, items[1].ID))
+		assertCount(t, store, 2, FilterByArchivedStatus(helpers.Ptr(true)))
+
+		require.NoError(t, store.MarkAsUnarchivedByID(ctx, subscriber, SourceGRPC, items[1].ID))
+		assertCount(t, store, 1, FilterByArchivedStatus(helpers.Ptr(true)))
+		assertCount(t, store, 1, FilterByUnarchivedStatus(helpers.Ptr(true)))
+		assertCount(t, store, 2, FilterByUnarchivedStatus(helpers.Ptr(false)))
+
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID))
+		assertCount(t, store, 0, FilterByUnarchivedStatus(helpers.Ptr(true)))
+
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, time.Now().Add(time.Minute)))
+		assertCount(t, store, 3, FilterByArchivedStatus(helpers.Ptr(true)))
+	})
+
+	t.Run("archive reasons", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 5)
+		left := storeTestItem(subscriber, "left", ProposalStateActive, 1)
+		left.DaoID = uuid.NameSpaceURL
+		require.NoError(t, store.CreateOrUpdate(ctx, &left))
+
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[0].ID))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedVoted, items[1].ID))
+		_, err := store.AutoArchive(ctx, []Item{items[2]})
+		require.NoError(t, err)
+		require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceGRPC, left.DaoID, UnsubscribeArchive))
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, time.Now().Add(time.Minute)))
+
+		reasons := func() map[string]ArchiveReason {
+			list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber)})
+			require.NoError(t, err)
+
+			reasons := make(map[string]ArchiveReason)
+			for _, item := range list {
+				reasons[item.ProposalID] = item.ArchiveReason
+			}
+
+			return reasons
+		}
+		assert.Equal(t, map[string]ArchiveReason{
+			items[0].ProposalID: ArchivedManually,
+			items[1].ProposalID: ArchivedVoted,
+			items[2].ProposalID: ArchivedExpired,
+			items[3].ProposalID: ArchivedBulkByTime,
+			items[4].ProposalID: ArchivedBulkByTime,
+			left.ProposalID:     ArchivedUnsubscribed,
+		}, reasons())
+		assertCount(t, store, 2, FilterBySubscriberID(subscriber), FilterByArchiveReasons(ArchivedVoted, ArchivedExpired))
+
+		// unarchived items don't have the reason
+		require.NoError(t, store.MarkAsUnarchivedByID(ctx, subscriber, SourceGRPC, items[0].ID))
+		endsSoon := items[1]
+		endsSoon.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingEndsSoon}}
+		require.NoError(t, store.CreateOrUpdate(ctx, &endsSoon))
+		assert.Empty(t, endsSoon.ArchiveReason)
+
+		stored := reasons()
+		assert.Empty(t, stored[items[0].ProposalID])
+		assert.Empty(t, stored[items[1].ProposalID])
+		assert.Equal(t, ArchivedExpired, stored[items[2].ProposalID])
+	})
+
+	t.Run("spam and canceled", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+
+		spam := storeTestItem(subscriber, "spam", ProposalStateActive, 1)
+		spam.Snapshot = []byte(`{"state":"active","created":1,"spam":true}`)
+		canceled := storeTestItem(subscriber, "canceled", ProposalStateCanceled, 1)
+		regular := storeTestItem(subscriber, "regular", ProposalStateActive, 1)
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{spam, canceled, regular}))
+
+		assertCount(t, store, 3)
+		assertCount(t, store, 2, SkipSpammed())
+		assertCount(t, store, 2, SkipCanceled())
+		assertCount(t, store, 1, SkipSpammed(), SkipCanceled())
+	})
+
+	t.Run("discussions", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, other := uuid.New(), uuid.New()
+
+		proposal := storeTestItem(subscriber, "proposal", ProposalStateCanceled, 1)
+		discussion := func(subscriberID uuid.UUID, discussionID string) Item {
+			item := storeTestItem(subscriberID, "", ProposalStateActive, 2)
+			item.DiscussionID = discussionID
+			item.Type = Discussion
+			item.Action = DiscussionCreated
+			item.Snapshot = []byte(`{"created":2}`)
+
+			return item
+		}
+		// discussions without the proposal don't collide with each other
+		first, second := discussion(subscriber, "first"), discussion(subscriber, "second")
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{proposal, first, second, discussion(other, "first")}))
+		assertCount(t, store, 3, FilterBySubscriberID(subscriber))
+
+		first.Action = DiscussionReplied
+		first.Snapshot = []byte(`{"created":2,"spam":true}`)
+		require.NoError(t, store.CreateOrUpdate(ctx, &first))
+		assertCount(t, store, 3, FilterBySubscriberID(subscriber))
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), FilterByActions(DiscussionReplied))
+		assert.Equal(t, []string{SubjectItemCreated, SubjectItemCreated, SubjectItemCreated, SubjectItemCreated, SubjectItemUpdated}, publishOutbox(t, store))
+
+		subscribers, err := store.FindSubscribersByDiscussionID(ctx, "first")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{subscriber, other}, subscribers)
+
+		// discussions don't have the state and the spam flag is optional
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), SkipSpammed(), SkipCanceled())
+		assertCount(t, store, 2, FilterBySubscriberID(subscriber), FilterByType(Discussion))
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), SkipDiscussions())
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), FilterByDiscussionIDs("second", "unknown"))
+		assertCounters(t, store, subscriber)
+	})
+
+	t.Run("proposal links the discussion", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+
+		proposal := storeTestItem(subscriber, "proposal", ProposalStateActive, 1)
+		discussion := storeTestItem(subscriber, "", ProposalStateActive, 1)
+		discussion.DiscussionID = "first"
+		discussion.Type = Discussion
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{proposal, discussion}))
+
+		// the proposal is the same item whatever discussion it links
+		linked := proposal
+		linked.DiscussionID = "first"
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{linked}))
+		relinked := proposal
+		relinked.DiscussionID = "second"
+		require.NoError(t, store.CreateOrUpdate(ctx, &relinked))
+
+		assertCount(t, store, 2, FilterBySubscriberID(subscriber))
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber), FilterByProposalID("proposal")})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "second", list[0].DiscussionID)
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber), FilterByType(Discussion), FilterByDiscussionIDs("first"))
+		assertCounters(t, store, subscriber)
+
+		assert.Equal(t, []string{SubjectItemCreated, SubjectItemCreated, SubjectItemUpdated, SubjectItemUpdated}, publishOutbox(t, store))
+	})
+
+	t.Run("dao events", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, other := uuid.New(), uuid.New()
+
+		require.NoError(t, store.SetDaoEvents(ctx, subscriber, true))
+		require.NoError(t, store.SetDaoEvents(ctx, other, false))
+		subscribers, err := store.FindDaoEventsSubscribers(ctx, []uuid.UUID{subscriber, other, uuid.New()})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{subscriber}, subscribers)
+
+		// the autoarchive setting doesn't reset the opt-in
+		require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: subscriber, AutoarchiveAfterDays: 3, DaoEvents: true}))
+		set, err := store.GetFeedSettings(ctx, subscriber)
+		require.NoError(t, err)
+		assert.True(t, set.DaoEvents)
+		assert.Equal(t, 3, set.AutoarchiveAfterDays)
+
+		daoItem := storeTestItem(subscriber, "", ProposalStateActive, 1)
+		daoItem.Type = Dao
+		daoItem.Action = DaoUpdated
+		daoItem.Snapshot = []byte(`{"name":"dao"}`)
+		proposal := storeTestItem(subscriber, "proposal", ProposalStateActive, 1)
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{daoItem, proposal}))
+		assert.Equal(t, []string{SubjectItemCreated, SubjectItemCreated}, publishOutbox(t, store))
+
+		subscribers, err = store.FindSubscribersByDaoItem(ctx, daoItem.DaoID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{subscriber}, subscribers)
+
+		// DAO-level items don't have the state and the spam flag
+		assertCount(t, store, 2, FilterBySubscriberID(subscriber), SkipSpammed(), SkipCanceled())
+		assertCounters(t, store, subscriber)
+
+		require.NoError(t, store.SetDaoEvents(ctx, subscriber, false))
+		assert.Equal(t, []string{SubjectItemDeleted}, publishOutbox(t, store))
+		assertCount(t, store, 1, FilterBySubscriberID(subscriber))
+		assertCount(t, store, 0, FilterByType(Dao))
+		assertCounters(t, store, subscriber)
+
+		subscribers, err = store.FindDaoEventsSubscribers(ctx, []uuid.UUID{subscriber})
+		require.NoError(t, err)
+		assert.Empty(t, subscribers)
+	})
+
+	t.Run("filter by ids", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+
+		assertCount(t, store, 2, FilterByIDs(items[0].ID, items[2].ID))
+		assertCount(t, store, 2, FilterByProposalIDs(items[0].ProposalID, items[1].ProposalID, "unknown"))
+		assertCount(t, store, 0, FilterByIDs(items[0].ID), FilterByProposalIDs(items[1].ProposalID))
+	})
+
+	t.Run("view filters", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, dao := uuid.New(), uuid.New()
+
+		endsSoon := storeTestItem(subscriber, "ends-soon", ProposalStateActive, 1)
+		endsSoon.DaoID = dao
+		endsSoon.Action = ProposalVotingEndsSoon
+		succeeded := storeTestItem(subscriber, "succeeded", ProposalStateSucceeded, 1)
+		succeeded.DaoID = dao
+		pending := storeTestItem(subscriber, "pending", ProposalStatePending, 1)
+		daoItem := storeTestItem(subscriber, "", ProposalStateActive, 1)
+		daoItem.Type = Dao
+		daoItem.Action = DaoUpdated
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{endsSoon, succeeded, pending, daoItem}))
+
+		assertCount(t, store, 2, FilterByDaoIDs(dao))
+		assertCount(t, store, 4, FilterByDaoIDs(dao, uuid.NameSpaceOID))
+		assertCount(t, store, 2, FilterByActions(ProposalVotingEndsSoon, DaoUpdated))
+		assertCount(t, store, 3, FilterByType(Proposal))
+		assertCount(t, store, 2, FilterByProposalStates(ProposalStateActive))
+		assertCount(t, store, 2, FilterByProposalStates(ProposalStateActive, ProposalStatePending), FilterByType(Proposal))
+		assertCount(t, store, 1, FilterByDaoIDs(dao), FilterByProposalStates(ProposalStateActive))
+	})
+
+	t.Run("count by dao", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		first, second := uuid.MustParse("00000000-0000-0000-0000-000000000001"), uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+		items := []Item{
+			storeTestItem(subscriber, "first-read", ProposalStateActive, 1),
+			storeTestItem(subscriber, "first-unread", ProposalStateActive, 2),
+			storeTestItem(subscriber, "second-unread", ProposalStateActive, 3),
+			storeTestItem(subscriber, "second-canceled", ProposalStateCanceled, 4),
+			storeTestItem(uuid.New(), "other-subscriber", ProposalStateActive, 5),
+		}
+		for i := range items {
+			items[i].DaoID = first
+		}
+		items[2].DaoID, items[3].DaoID = second, second
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, items))
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID))
+
+		list, err := store.CountByDao(ctx, []Filter{FilterBySubscriberID(subscriber), SkipCanceled()})
+		require.NoError(t, err)
+		assert.Equal(t, []DaoCounters{
+			{DaoID: first, TotalCount: 2, UnreadCount: 1},
+			{DaoID: second, TotalCount: 1, UnreadCount: 1},
+		}, list)
+
+		list, err = store.CountByDao(ctx, []Filter{FilterBySubscriberID(uuid.New())})
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("actuality order and cursor", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+
+		items := []Item{
+			storeTestItem(subscriber, "succeeded", ProposalStateSucceeded, 100),
+			storeTestItem(subscriber, "unknown", "closed", 300),
+			storeTestItem(subscriber, "active-old", ProposalStateActive, 100),
+			storeTestItem(subscriber, "pending", ProposalStatePending, 200),
+			storeTestItem(subscriber, "active-new", ProposalStateActive, 200),
+			storeTestItem(subscriber, "active-same-2", ProposalStateActive, 150),
+			storeTestItem(subscriber, "active-same-1", ProposalStateActive, 150),
+			storeTestItem(subscriber, "active-float", ProposalStateActive, 0),
+		}
+		// the same rank and created are ordered by id
+		items[5].ID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+		items[6].ID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+		// malformed created doesn't break the query and goes last
+		items[7].Snapshot = []byte(`{"state":"active","created":1700000000.5,"spam":false}`)
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, items))
+
+		expected := []string{"active-new", "active-same-1", "active-same-2", "active-old", "active-float", "pending", "succeeded", "unknown"}
+
+		list, err := store.FindByFilters(ctx, []Filter{SortedByActuality()})
+		require.NoError(t, err)
+		assert.Equal(t, expected, proposalIDs(list))
+
+		var (
+			paged  []string
+			filter []Filter
+		)
+		for page := 0; page < len(expected); page++ {
+			list, err = store.FindByFilters(ctx, append([]Filter{SortedByActuality(), WithLimit(3, 0)}, filter...))
+			require.NoError(t, err)
+			if len(list) == 0 {
+				break
+			}
+
+			paged = append(paged, proposalIDs(list)...)
+
+			cursor, err := NewCursor(list[len(list)-1])
+			require.NoError(t, err)
+			filter = []Filter{FilterAfterCursor(cursor)}
+		}
+		assert.Equal(t, expected, paged)
+	})
+
+	t.Run("legacy actuality order", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+
+		items := []Item{
+			storeTestItem(subscriber, "failed", ProposalStateFailed, 300),
+			storeTestItem(subscriber, "active-old", ProposalStateActive, 100),
+			storeTestItem(subscriber, "active-new", ProposalStateActive, 200),
+			storeTestItem(subscriber, "active-without-created", ProposalStateActive, 0),
+		}
+		items[3].Snapshot = []byte(`{"state":"active","spam":false}`)
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, items))
+
+		list, err := store.FindByFilters(ctx, []Filter{SortedByLegacyActuality()})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"active-without-created", "active-new", "active-old", "failed"}, proposalIDs(list))
+	})
+
+	t.Run("limit and offset", func(t *testing.T) {
+		store := newStore(t)
+		storeTestItems(t, store, uuid.New(), 5)
+
+		list, err := store.FindByFilters(ctx, []Filter{SortedByActuality(), WithLimit(2, 1)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"proposal-3", "proposal-2"}, proposalIDs(list))
+
+		assertCount(t, store, 5, WithLimit(2, 0))
+		assertCount(t, store, 0, WithLimit(2, 1))
+	})
+
+	t.Run("auto archive", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, other := uuid.New(), uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+		others := storeTestItems(t, store, other, 1)
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[2].ID))
+		publishOutbox(t, store)
+
+		// the item of the other subscriber has the same id in the feed of the subscriber
+		stranger := others[0]
+		stranger.SubscriberID = subscriber
+
+		archived, err := store.AutoArchive(ctx, []Item{items[0], items[2], others[0], stranger})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{subscriber, other}, itemSubscribers(archived))
+		assert.Len(t, archived, 2)
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterByArchivedStatus(helpers.Ptr(true)), FilterBySubscriberID(subscriber)})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{items[0].ProposalID, items[2].ProposalID}, proposalIDs(list))
+		assertCount(t, store, 1, FilterByArchivedStatus(helpers.Ptr(true)), FilterBySubscriberID(other))
+
+		assertCounters(t, store, subscriber)
+		assertCounters(t, store, other)
+		assert.Equal(t, []string{SubjectItemAutoArchived, SubjectItemAutoArchived}, publishOutbox(t, store))
+	})
+
+	t.Run("auto archive candidates", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		now := time.Now()
+
+		var items []Item
+		for proposalID, snapshot := range map[string]string{
+			"active":        fmt.Sprintf(`{"state":"active","end":%d}`, now.Add(time.Hour).Unix()),
+			"active-ended":  fmt.Sprintf(`{"state":"active","end":%d.5}`, now.Unix()),
+			"defeated":      fmt.Sprintf(`{"state":"defeated","end":%d}`, now.Add(time.Hour).Unix()),
+			"no-state":      fmt.Sprintf(`{"end":%d}`, now.Add(-time.Hour).Unix()),
+			"text-end":      fmt.Sprintf(`{"state":"pending","end":"%d"}`, now.Add(-time.Hour).Unix()),
+			"no-end":        `{"state":"pending"}`,
+			"malformed-end": `{"state":"active","end":{"at":1}}`,
+		} {
+			item := storeTestItem(subscriber, proposalID, ProposalStateActive, 1)
+			item.Snapshot = []byte(snapshot)
+			items = append(items, item)
+		}
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, items))
+
+		list, err := store.FindByFilters(ctx, []Filter{
+			FilterBySubscriberID(subscriber),
+			FilterAutoArchiveCandidates(now),
+			WithAutoArchiveFields(),
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"active-ended", "defeated", "no-state"}, proposalIDs(list))
+
+		// rules get every field they use
+		for _, item := range list {
+			var snapshot autoArchiveSnapshot
+			require.NoError(t, json.Unmarshal(item.Snapshot, &snapshot))
+			assert.NotZero(t, snapshot.End)
+			assert.Equal(t, subscriber, item.SubscriberID)
+			assert.Equal(t, uuid.NameSpaceOID, item.DaoID)
+			assert.Equal(t, Proposal, item.Type)
+			assert.False(t, item.CreatedAt.IsZero())
+		}
+	})
+
+	t.Run("item history", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, other := uuid.New(), uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+		others := storeTestItems(t, store, other, 1)
+
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, others[0].ID))
+		require.NoError(t, store.MarkAsReadByID(ctx, other, SourceGRPC, others[0].ID))
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceVoteConsumer, items[1].ID))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceVoteConsumer, ArchivedVoted, items[1].ID))
+		require.NoError(t, store.MarkAsUnarchivedByID(ctx, subscriber, SourceGRPC, items[1].ID))
+		require.NoError(t, store.MarkAsUnreadByID(ctx, subscriber, SourceGRPC, items[1].ID))
+		_, err := store.AutoArchive(ctx, items[2:])
+		require.NoError(t, err)
+		// nothing is changed, so nothing is recorded
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-2*time.Hour)))
+
+		history, err := store.FindItemHistory(ctx, subscriber, uuid.Nil, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, history, 6)
+		for idx, expected := range []ItemHistoryEvent{
+			{ItemID: items[2].ID, Transition: TransitionArchived, Source: SourceWorker, ArchiveReason: ArchivedExpired},
+			{ItemID: items[1].ID, Transition: TransitionUnread, Source: SourceGRPC},
+			{ItemID: items[1].ID, Transition: TransitionUnarchived, Source: SourceGRPC},
+			{ItemID: items[1].ID, Transition: TransitionArchived, Source: SourceVoteConsumer, ArchiveReason: ArchivedVoted},
+			{ItemID: items[1].ID, Transition: TransitionRead, Source: SourceVoteConsumer},
+			{ItemID: items[0].ID, Transition: TransitionRead, Source: SourceGRPC},
+		} {
+			actual := history[idx]
+			assert.Equal(t, subscriber, actual.SubscriberID)
+			assert.Equal(t, expected.ItemID, actual.ItemID, idx)
+			assert.Equal(t, expected.Transition, actual.Transition, idx)
+			assert.Equal(t, expected.Source, actual.Source, idx)
+			assert.Equal(t, expected.ArchiveReason, actual.ArchiveReason, idx)
+			assert.False(t, actual.OccurredAt.IsZero())
+		}
+
+		page, err := store.FindItemHistory(ctx, subscriber, uuid.Nil, history[2].ID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, history[3:5], page)
+
+		page, err = store.FindItemHistory(ctx, subscriber, items[1].ID, history[0].ID, 10)
+		require.NoError(t, err)
+		assert.Equal(t, history[1:5], page)
+
+		page, err = store.FindItemHistory(ctx, other, uuid.Nil, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, others[0].ID, page[0].ItemID)
+	})
+
+	t.Run("item history of updates, wake and unsubscribe", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 4)
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID, items[2].ID, items[3].ID))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID))
+
+		quorum := items[0]
+		quorum.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingQuorumReached}}
+		require.NoError(t, store.CreateOrUpdate(ctx, &quorum))
+		endsSoon := items[1]
+		endsSoon.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingEndsSoon}}
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{endsSoon}))
+
+		until := time.Now().Add(time.Hour)
+		require.NoError(t, store.Snooze(ctx, subscriber, Snooze{Until: &until}, items[2].ID))
+		_, err := store.WakeSnoozed(ctx, until)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceSubscriptionConsumer, items[3].DaoID, UnsubscribeArchive))
+
+		unsubscribed := ItemHistoryEvent{Transition: TransitionArchived, Source: SourceSubscriptionConsumer, ArchiveReason: ArchivedUnsubscribed}
+		read := ItemHistoryEvent{Transition: TransitionRead, Source: SourceGRPC}
+		for idx, expected := range [][]ItemHistoryEvent{
+			{unsubscribed, {Transition: TransitionUnread, Source: SourceFeedUpdate}, read},
+			{
+				unsubscribed,
+				{Transition: TransitionUnread, Source: SourceFeedUpdate},
+				{Transition: TransitionUnarchived, Source: SourceFeedUpdate},
+				{Transition: TransitionArchived, Source: SourceGRPC, ArchiveReason: ArchivedManually},
+				read,
+			},
+			{unsubscribed, {Transition: TransitionUnread, Source: SourceWorker}, read},
+			{unsubscribed, read},
+		} {
+			history, err := store.FindItemHistory(ctx, subscriber, items[idx].ID, 0, 10)
+			require.NoError(t, err)
+
+			actual := make([]ItemHistoryEvent, 0, len(history))
+			for _, event := range history {
+				actual = append(actual, ItemHistoryEvent{Transition: event.Transition, Source: event.Source, ArchiveReason: event.ArchiveReason})
+			}
+			assert.Equal(t, expected, actual, idx)
+		}
+	})
+
+	t.Run("primary key order", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, other := uuid.New(), uuid.New()
+		items := append(storeTestItems(t, store, subscriber, 3), storeTestItems(t, store, other, 2)...)
+
+		var (
+			walked []Item
+			after  *Item
+		)
+		for {
+			filters := []Filter{SortedByPrimaryKey(), WithLimit(2, 0)}
+			if after != nil {
+				filters = append(filters, FilterAfterPrimaryKey(after.ID, after.SubscriberID))
+			}
+
+			batch, err := store.FindByFilters(ctx, filters)
+			require.NoError(t, err)
+			walked = append(walked, batch...)
+			if len(batch) < 2 {
+				break
+			}
+
+			after = &batch[len(batch)-1]
+		}
+
+		require.Len(t, walked, len(items))
+		assert.True(t, slices.IsSortedFunc(walked, func(a, b Item) int {
+			return comparePrimaryKeys(&a, &b)
+		}))
+	})
+
+	t.Run("advisory lock", func(t *testing.T) {
+		store := newStore(t)
+
+		release, acquired, err := store.TryLock(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		_, acquired, err = store.TryLock(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, acquired, "the lock is held")
+
+		other, acquired, err := store.TryLock(ctx, 2)
+		require.NoError(t, err)
+		require.True(t, acquired, "other keys are not locked")
+		require.NoError(t, other())
+
+		require.NoError(t, release())
+		release, acquired, err = store.TryLock(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, acquired, "the lock is released")
+		require.NoError(t, release())
+	})
+
+	t.Run("job runs", func(t *testing.T) {
+		store := newStore(t)
+
+		last, err := store.LastJobRun(ctx, "job")
+		require.NoError(t, err)
+		assert.True(t, last.IsZero(), "never run")
+
+		first := time.Now().Add(-time.Hour)
+		require.NoError(t, store.SaveJobRun(ctx, "job", first))
+		second := time.Now()
+		require.NoError(t, store.SaveJobRun(ctx, "job", second))
+
+		last, err = store.LastJobRun(ctx, "job")
+		require.NoError(t, err)
+		assert.WithinDuration(t, second, last, time.Millisecond)
+
+		last, err = store.LastJobRun(ctx, "other")
+		require.NoError(t, err)
+		assert.True(t, last.IsZero(), "jobs are stored separately")
+	})
+
+	t.Run("snooze", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 4)
+		ids := []uuid.UUID{items[0].ID, items[1].ID, items[2].ID, items[3].ID}
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, ids...))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[3].ID))
+		publishOutbox(t, store)
+
+		until := time.Now().Add(time.Hour)
+		require.NoError(t, store.Snooze(ctx, subscriber, Snooze{Until: &until}, items[0].ID))
+		require.NoError(t, store.Snooze(ctx, subscriber, Snooze{UntilAction: ProposalVotingEndsSoon}, items[1].ID))
+		later := until.Add(time.Hour)
+		require.NoError(t, store.Snooze(ctx, subscriber, Snooze{Until: &later}, items[2].ID, items[3].ID))
+		assertCount(t, store, 3, FilterBySubscriberID(subscriber), FilterBySnoozedStatus(helpers.Ptr(true)))
+		assertCounters(t, store, subscriber)
+
+		// the time hasn't come yet
+		subscribers, err := store.WakeSnoozed(ctx, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, subscribers)
+
+		subscribers, err = store.WakeSnoozed(ctx, until)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{subscriber}, subscribers)
+
+		endsSoon := items[1]
+		endsSoon.Timeline = Timeline{{CreatedAt: time.Now(), Action: ProposalVotingEndsSoon}}
+		require.NoError(t, store.CreateOrUpdate(ctx, &endsSoon))
+		assert.Nil(t, endsSoon.SnoozedAt)
+
+		require.NoError(t, store.Snooze(ctx, subscriber, Snooze{Until: &until}, items[2].ID))
+		require.NoError(t, store.Unsnooze(ctx, subscriber, items[2].ID))
+
+		list, err := store.FindByFilters(ctx, []Filter{FilterBySubscriberID(subscriber), FilterBySnoozedStatus(helpers.Ptr(false))})
+		require.NoError(t, err)
+		require.Len(t, list, 4)
+		stored := make(map[string]Item)
+		for _, item := range list {
+			stored[item.ProposalID] = item
+			assert.Nil(t, item.SnoozedUntil)
+			assert.Empty(t, item.SnoozedUntilAction)
+		}
+
+		assert.Nil(t, stored[items[0].ProposalID].ReadAt, "woken by time")
+		assert.Nil(t, stored[items[1].ProposalID].ReadAt, "woken by milestone")
+		assert.NotNil(t, stored[items[2].ProposalID].ReadAt, "unsnoozed keeps the read state")
+		assert.NotNil(t, stored[items[3].ProposalID].ArchivedAt, "archived items aren't snoozed")
+		assertCounters(t, store, subscriber)
+
+		assert.Equal(t, []string{
+			SubjectItemSnoozed, SubjectItemSnoozed, SubjectItemSnoozed,
+			SubjectItemUnsnoozed,
+			SubjectItemUpdated, SubjectItemUnsnoozed,
+			SubjectItemSnoozed, SubjectItemUnsnoozed,
+		}, publishOutbox(t, store))
+	})
+
+	t.Run("archive clears the snooze", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 4)
+		ids := []uuid.UUID{items[0].ID, items[1].ID, items[2].ID, items[3].ID}
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, ids...))
+
+		until := time.Now().Add(time.Hour)
+		require.NoError(t, store.Snooze(ctx, subscriber, Snooze{Until: &until}, ids...))
+
+		archived, err := store.AutoArchive(ctx, []Item{items[2]})
+		require.NoError(t, err)
+		require.Len(t, archived, 1)
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[0].ID))
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, items[1].CreatedAt))
+		// the rest of items are archived already
+		require.NoError(t, store.Unsubscribe(ctx, subscriber, SourceGRPC, items[3].DaoID, UnsubscribeArchive))
+
+		assertCount(t, store, 0, FilterBySubscriberID(subscriber), FilterBySnoozedStatus(helpers.Ptr(true)))
+		assertCounters(t, store, subscriber)
+
+		// archived items stay read
+		subscribers, err := store.WakeSnoozed(ctx, until)
+		require.NoError(t, err)
+		assert.Empty(t, subscribers)
+		assertCount(t, store, 0, FilterBySubscriberID(subscriber), FilterByReadStatus(helpers.Ptr(false)))
+	})
+
+	t.Run("counters", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+
+		counters, err := store.GetCounters(ctx, subscriber)
+		require.NoError(t, err)
+		assert.Equal(t, Counters{SubscriberID: subscriber}, counters)
+
+		items := storeTestItems(t, store, subscriber, 4)
+		spam := storeTestItem(subscriber, "spam", ProposalStateActive, 1)
+		spam.Snapshot = []byte(`{"state":"active","created":1,"spam":true}`)
+		require.NoError(t, store.CreateOrUpdate(ctx, &spam))
+		assertCounters(t, store, subscriber)
+
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID, spam.ID))
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID))
+		assertCounters(t, store, subscriber)
+
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID, items[2].ID))
+		assertCounters(t, store, subscriber)
+
+		canceled := items[3]
+		canceled.Snapshot = storeTestSnapshot(ProposalStateCanceled, 3)
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, []Item{canceled}))
+		assertCounters(t, store, subscriber)
+
+		require.NoError(t, store.MarkAsUnreadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-time.Hour)))
+		require.NoError(t, store.MarkAsUnarchivedByID(ctx, subscriber, SourceGRPC, items[2].ID))
+		assertCounters(t, store, subscriber)
+
+		require.NoError(t, store.MarkAsReadByTime(ctx, subscriber, SourceGRPC, time.Now()))
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, time.Now()))
+		assertCounters(t, store, subscriber)
+
+		counters, err = store.GetCounters(ctx, subscriber)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), counters.ArchivedCount)
+
+		subscribers, fixed, err := store.ReconcileCounters(ctx, uuid.Nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{subscriber}, subscribers)
+		assert.Empty(t, fixed, "counters are kept up to date")
+	})
+
+	t.Run("reconcile counters by batches", func(t *testing.T) {
+		store := newStore(t)
+		first, second := uuid.MustParse("00000000-0000-0000-0000-000000000001"), uuid.MustParse("00000000-0000-0000-0000-000000000002")
+		storeTestItems(t, store, first, 1)
+		storeTestItems(t, store, second, 2)
+
+		subscribers, fixed, err := store.ReconcileCounters(ctx, uuid.Nil, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first}, subscribers)
+		assert.Empty(t, fixed)
+
+		subscribers, fixed, err = store.ReconcileCounters(ctx, first, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{second}, subscribers)
+		assert.Empty(t, fixed)
+
+		subscribers, _, err = store.ReconcileCounters(ctx, second, 10)
+		require.NoError(t, err)
+		assert.Empty(t, subscribers)
+	})
+
+	t.Run("outbox", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 2)
+		assert.Equal(t, []string{SubjectItemCreated, SubjectItemCreated}, publishOutbox(t, store))
+
+		require.NoError(t, store.BulkCreateOrUpdate(ctx, items[:1]))
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID))
+		require.NoError(t, store.MarkAsReadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(time.Minute)))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID))
+		require.NoError(t, store.MarkAsUnarchivedByID(ctx, subscriber, SourceGRPC, items[1].ID))
+		require.NoError(t, store.MarkAsUnreadByID(ctx, uuid.New(), SourceGRPC, items[1].ID))
+
+		var published []ItemEvent
+		count, err := store.PublishOutbox(ctx, 3, func(event OutboxEvent) error {
+			if len(published) == 2 {
+				return errors.New("unavailable")
+			}
+
+			var payload ItemEvent
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			published = append(published, payload)
+
+			return nil
+		})
+		require.Error(t, err)
+		assert.Equal(t, 2, count)
+		require.Len(t, published, 2)
+		assert.Equal(t, ItemEventVersion, published[0].Version)
+		assert.Equal(t, items[0].ID, published[0].ID)
+		assert.Equal(t, subscriber, published[0].SubscriberID)
+		assert.Equal(t, items[0].ProposalID, published[0].ProposalID)
+		assert.Equal(t, items[0].ID, published[1].ID)
+
+		// the rest of events is published in order by the next call, only the unread item is marked by time
+		assert.Equal(t, []string{SubjectItemRead, SubjectItemArchived, SubjectItemUnarchived}, publishOutbox(t, store))
+	})
+
+	t.Run("outbox of marks by time", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+		items := storeTestItems(t, store, subscriber, 3)
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID))
+		require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID))
+		publishOutbox(t, store)
+
+		// only items which change their state produce events
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, store.MarkAsReadByTime(ctx, subscriber, SourceGRPC, later))
+		assert.Equal(t, []string{SubjectItemRead, SubjectItemRead}, publishOutbox(t, store))
+		require.NoError(t, store.MarkAsReadByTime(ctx, subscriber, SourceGRPC, later))
+		assert.Empty(t, publishOutbox(t, store))
+
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, later))
+		assert.Equal(t, []string{SubjectItemArchived, SubjectItemArchived}, publishOutbox(t, store))
+		require.NoError(t, store.MarkAsArchivedByTime(ctx, subscriber, SourceGRPC, later))
+		assert.Empty(t, publishOutbox(t, store))
+
+		require.NoError(t, store.MarkAsUnreadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-2*time.Hour)))
+		assert.Len(t, publishOutbox(t, store), 3)
+		require.NoError(t, store.MarkAsUnreadByTime(ctx, subscriber, SourceGRPC, time.Now().Add(-2*time.Hour)))
+		assert.Empty(t, publishOutbox(t, store))
+	})
+
+	// regression: settings were read without the subscriber condition, so the first stored row was returned
+	t.Run("settings lookup by subscriber", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, other := uuid.New(), uuid.New()
+		require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: other, AutoarchiveAfterDays: 1}))
+
+		_, err := store.GetFeedSettings(ctx, subscriber)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: subscriber, AutoarchiveAfterDays: 7}))
+
+		set, err := store.GetFeedSettings(ctx, subscriber)
+		require.NoError(t, err)
+		assert.Equal(t, subscriber, set.SubscriberID)
+		assert.Equal(t, 7, set.AutoarchiveAfterDays)
+
+		set, err = store.GetFeedSettings(ctx, other)
+		require.NoError(t, err)
+		assert.Equal(t, 1, set.AutoarchiveAfterDays)
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		store := newStore(t)
+		subscriber := uuid.New()
+
+		_, err := store.GetFeedSettings(ctx, subscriber)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: uuid.New(), AutoarchiveAfterDays: 1}))
+		require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: subscriber, AutoarchiveAfterDays: 3}))
+		require.NoError(t, store.StoreSettings(ctx, &Settings{SubscriberID: subscriber, AutoarchiveAfterDays: 5}))
+
+		set, err := store.GetFeedSettings(ctx, subscriber)
+		require.NoError(t, err)
+		assert.Equal(t, subscriber, set.SubscriberID)
+		assert.Equal(t, 5, set.AutoarchiveAfterDays)
+		assert.Equal(t, defaultAutoArchiveRules(5), set.EffectiveAutoarchiveRules())
+
+		rules := AutoArchiveRules{
+			{Kind: AutoArchiveStates, States: []string{ProposalStateFailed, ProposalStateDefeated}},
+			{Kind: AutoArchiveNeverDao, DaoIDs: []uuid.UUID{uuid.NameSpaceOID}},
+		}
+		fresh := uuid.New()
+		require.NoError(t, store.SetAutoArchiveRules(ctx, subscriber, rules))
+		require.NoError(t, store.SetAutoArchiveRules(ctx, fresh, rules))
+
+		list, err := store.FindSettings(ctx, []uuid.UUID{subscriber, fresh, uuid.New()})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		for _, set := range list {
+			assert.Equal(t, rules, set.EffectiveAutoarchiveRules())
+		}
+
+		require.NoError(t, store.SetAutoArchiveRules(ctx, subscriber, nil))
+		set, err = store.GetFeedSettings(ctx, subscriber)
+		require.NoError(t, err)
+		assert.Equal(t, 5, set.AutoarchiveAfterDays)
+		assert.Equal(t, defaultAutoArchiveRules(5), set.EffectiveAutoarchiveRules())
+	})
+}
+
+func storeTestSnapshot(state string, created int64) json.RawMessage {
+	return []byte(fmt.Sprintf(`{"state":%q,"created":%d,"spam":false}`, state, created))
+}
+
+func storeTestItem(subscriberID uuid.UUID, proposalID, state string, created int64) Item {
+	return Item{
+		ID:           uuid.New(),
+		SubscriberID: subscriberID,
+		CreatedAt:    time.Now().Add(-time.Hour),
+		UpdatedAt:    time.Now().Add(-time.Hour),
+		DaoID:        uuid.NameSpaceOID,
+		ProposalID:   proposalID,
+		Type:         Proposal,
+		Action:       ProposalCreated,
+		Snapshot:     storeTestSnapshot(state, created),
+		Timeline:     Timeline{},
+	}
+}
+
+// storeTestItems stores active proposals, the latest item is the most actual one.
+func storeTestItems(t *testing.T, store FeedStore, subscriberID uuid.UUID, count int) []Item {
+	items := make([]Item, 0, count)
+	for i := 0; i < count; i++ {
+		items = append(items, storeTestItem(subscriberID, fmt.Sprintf("proposal-%d", i), ProposalStateActive, int64(i)))
+	}
+
+	require.NoError(t, store.BulkCreateOrUpdate(context.Background(), items))
+
+	return items
+}
+
+// publishOutbox publishes all stored events and returns their subjects.
+func publishOutbox(t *testing.T, store OutboxStore) []string {
+	var subjects []string
+	_, err := store.PublishOutbox(context.Background(), 100, func(event OutboxEvent) error {
+		subjects = append(subjects, event.Subject)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return subjects
+}
+
+// assertCounters compares materialized counters with counters calculated by filters.
+func assertCounters(t *testing.T, store FeedStore, subscriberID uuid.UUID) {
+	t.Helper()
+
+	count := func(filters ...Filter) int64 {
+		count, err := store.CountByFilters(context.Background(), append(filters, FilterBySubscriberID(subscriberID)))
+		require.NoError(t, err)
+
+		return count
+	}
+
+	counters, err := store.GetCounters(context.Background(), subscriberID)
+	require.NoError(t, err)
+
+	counters.UpdatedAt = time.Time{}
+	assert.Equal(t, Counters{
+		SubscriberID:  subscriberID,
+		TotalCount:    count(countersFilters()...),
+		UnreadCount:   count(append(countersFilters(), FilterByReadStatus(helpers.Ptr(false)))...),
+		ArchivedCount: count(append(countedFilters(), FilterByArchivedStatus(helpers.Ptr(true)))...),
+	}, counters)
+}
+
+func assertCount(t *testing.T, store FeedStore, expected int64, filters ...Filter) {
+	t.Helper()
+
+	count, err := store.CountByFilters(context.Background(), filters)
+	require.NoError(t, err)
+	assert.Equal(t, expected, count)
+}
+
+func proposalIDs(list []Item) []string {
+	ids := make([]string, 0, len(list))
+	for _, item := range list {
+		ids = append(ids, item.ProposalID)
+	}
+
+	return ids
+}
@@ -0,0 +1,33 @@
+package feed
+
+import (
+	"fmt"
+)
+
+// UnsubscribeMode defines what happens to items of the dao when the subscriber leaves it.
+// Items are frozen in every mode, so they aren't updated anymore until the subscriber comes back.
+type UnsubscribeMode string
+
+const (
+	// UnsubscribePurge removes items of the dao from the feed
+	UnsubscribePurge UnsubscribeMode = "purge"
+	// UnsubscribeArchive archives items of the dao
+	UnsubscribeArchive UnsubscribeMode = "archive"
+	// UnsubscribeFreeze keeps items of the dao in the feed as they are
+	UnsubscribeFreeze UnsubscribeMode = "freeze"
+)
+
+// DefaultUnsubscribeMode is used when the subscriber hasn't chosen the mode.
+const DefaultUnsubscribeMode = UnsubscribeFreeze
+
+// ParseUnsubscribeMode converts the mode from the payload, empty value means the default mode.
+func ParseUnsubscribeMode(value string) (UnsubscribeMode, error) {
+	switch mode := UnsubscribeMode(value); mode {
+	case "":
+		return DefaultUnsubscribeMode, nil
+	case UnsubscribePurge, UnsubscribeArchive, UnsubscribeFreeze:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown unsubscribe mode: %s", value)
+	}
+}
@@ -0,0 +1,26 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnsubscribeMode(t *testing.T) {
+	for value, tc := range map[string]struct {
+		mode  UnsubscribeMode
+		valid bool
+	}{
+		"":        {mode: UnsubscribeFreeze, valid: true},
+		"purge":   {mode: UnsubscribePurge, valid: true},
+		"archive": {mode: UnsubscribeArchive, valid: true},
+		"freeze":  {mode: UnsubscribeFreeze, valid: true},
+		"delete":  {},
+	} {
+		t.Run(value, func(t *testing.T) {
+			mode, err := ParseUnsubscribeMode(value)
+			assert.Equal(t, tc.valid, err == nil)
+			assert.Equal(t, tc.mode, mode)
+		})
+	}
+}
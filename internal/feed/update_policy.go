@@ -0,0 +1,247 @@
+package feed
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/config"
+)
+
+// resurfaceStatePrefix prefixes the resurface reason caused by the change of the proposal state.
+const resurfaceStatePrefix = "state:"
+
+// UnarchivedManually is the unarchive reason of items unarchived by the subscriber.
+const UnarchivedManually = "manual"
+
+// ArchivedUpdateMode defines how updates which don't unarchive the item are applied to archived items.
+type ArchivedUpdateMode string
+
+const (
+	// ArchivedUpdateFreeze keeps archived items as they were archived
+	ArchivedUpdateFreeze ArchivedUpdateMode = "freeze"
+	// ArchivedUpdateSilent updates archived items, they stay archived and keep the read state
+	ArchivedUpdateSilent ArchivedUpdateMode = "update"
+)
+
+// updateOutcome is the result of the update of the stored item.
+type updateOutcome int
+
+const (
+	outcomeUpdated updateOutcome = iota
+	// outcomeFrozen means the archived item isn't changed by the update
+	outcomeFrozen
+	outcomeResurfaced
+	// outcomeUnarchived means the archived item is unarchived and resurfaced by the update
+	outcomeUnarchived
+	// outcomeWoken means the snoozed item is woken up by the milestone and marked as unread
+	outcomeWoken
+)
+
+// outcomeSubjects are subjects of all outcomes in the order their events are stored.
+var outcomeSubjects = []string{SubjectItemUnarchived, SubjectItemResurfaced, SubjectItemUnsnoozed}
+
+// outcomeTransitions are transitions of all outcomes in the order they are appended to the history.
+var outcomeTransitions = []ItemTransition{TransitionUnarchived, TransitionUnread}
+
+// transitions returns transitions the outcome appends to the item history.
+func (o updateOutcome) transitions() []ItemTransition {
+	switch o {
+	case outcomeResurfaced, outcomeWoken:
+		return []ItemTransition{TransitionUnread}
+	case outcomeUnarchived:
+		return []ItemTransition{TransitionUnarchived, TransitionUnread}
+	default:
+		return nil
+	}
+}
+
+// subjects returns subjects of events about the outcome in addition to the updated event.
+func (o updateOutcome) subjects() []string {
+	switch o {
+	case outcomeResurfaced:
+		return []string{SubjectItemResurfaced}
+	case outcomeUnarchived:
+		return []string{SubjectItemUnarchived, SubjectItemResurfaced}
+	case outcomeWoken:
+		return []string{SubjectItemUnsnoozed}
+	default:
+		return nil
+	}
+}
+
+// ResurfacePolicy decides if the update of the stored item is significant, so the item has to be shown
+// as unread again.
+type ResurfacePolicy struct {
+	// Actions resurface the item when they appear in its timeline
+	Actions []Action
+	// States resurface the item when the proposal moves to one of them
+	States []string
+}
+
+// Reason returns why the stored item has to be resurfaced after the update, it's empty if the update isn't significant.
+// The reason is the latest significant action appeared in the timeline, e.g. proposal.voting.quorum_reached,
+// or the new proposal state with the "state:" prefix, e.g. state:succeeded.
+func (p ResurfacePolicy) Reason(stored, updated *Item) string {
+	if action := latestNewAction(stored, updated, p.Actions); action != "" {
+		return string(action)
+	}
+
+	state, _ := snapshotText(updated, "state")
+	if prev, _ := snapshotText(stored, "state"); state != prev && slices.Contains(p.States, state) {
+		return resurfaceStatePrefix + state
+	}
+
+	return ""
+}
+
+// ArchivedPolicy defines how updates are applied to archived items.
+type ArchivedPolicy struct {
+	Mode ArchivedUpdateMode
+	// UnarchiveActions unarchive the item regardless of the mode when they appear in its timeline,
+	// unless the subscriber has voted on the proposal
+	UnarchiveActions []Action
+}
+
+// UnarchiveReason returns the action which unarchives the stored item after the update, it's empty if the item stays archived.
+func (p ArchivedPolicy) UnarchiveReason(stored, updated *Item) string {
+	if stored.VotedAt != nil {
+		return ""
+	}
+
+	return string(latestNewAction(stored, updated, p.UnarchiveActions))
+}
+
+// UpdatePolicy defines how updates from core change the read and archived state of stored items.
+type UpdatePolicy struct {
+	Resurface ResurfacePolicy
+	Archived  ArchivedPolicy
+}
+
+func NewUpdatePolicy(cfg config.Feed) (UpdatePolicy, error) {
+	mode := ArchivedUpdateMode(cfg.ArchivedUpdateMode)
+	if mode != ArchivedUpdateFreeze && mode != ArchivedUpdateSilent {
+		return UpdatePolicy{}, fmt.Errorf("unknown archived update mode: %s", cfg.ArchivedUpdateMode)
+	}
+
+	return UpdatePolicy{
+		Resurface: ResurfacePolicy{
+			Actions: convertActions(cfg.ResurfaceActions),
+			States:  cfg.ResurfaceStates,
+		},
+		Archived: ArchivedPolicy{
+			Mode:             mode,
+			UnarchiveActions: convertActions(cfg.UnarchiveActions),
+		},
+	}, nil
+}
+
+// apply prepares the update of the stored item: the updated item gets the read, archived and snoozed state
+// it has to be stored with. Frozen items must not be stored.
+func (p UpdatePolicy) apply(stored, updated *Item, now time.Time) updateOutcome {
+	if stored.FrozenAt != nil {
+		return outcomeFrozen
+	}
+
+	updated.ReadAt = stored.ReadAt
+	updated.ResurfacedAt = stored.ResurfacedAt
+	updated.ResurfacedReason = stored.ResurfacedReason
+	updated.ArchivedAt = stored.ArchivedAt
+	updated.ArchiveReason = stored.ArchiveReason
+	updated.UnarchivedAt = stored.UnarchivedAt
+	updated.UnarchivedReason = stored.UnarchivedReason
+	updated.SnoozedAt = stored.SnoozedAt
+	updated.SnoozedUntil = stored.SnoozedUntil
+	updated.SnoozedUntilAction = stored.SnoozedUntilAction
+
+	if stored.ArchivedAt != nil {
+		reason := p.Archived.UnarchiveReason(stored, updated)
+		if reason != "" {
+			updated.ArchivedAt = nil
+			updated.ArchiveReason = ""
+			updated.UnarchivedAt = &now
+			updated.UnarchivedReason = reason
+			resurface(updated, reason, now)
+
+			return outcomeUnarchived
+		}
+
+		if p.Archived.Mode == ArchivedUpdateSilent {
+			return outcomeUpdated
+		}
+
+		return outcomeFrozen
+	}
+
+	if stored.SnoozedAt != nil && stored.SnoozedUntilAction != "" &&
+		latestNewAction(stored, updated, []Action{stored.SnoozedUntilAction}) != "" {
+		wake(updated)
+
+		return outcomeWoken
+	}
+
+	// only read items come back, unread ones are shown as new anyway
+	if stored.ReadAt == nil {
+		return outcomeUpdated
+	}
+
+	if reason := p.Resurface.Reason(stored, updated); reason != "" {
+		resurface(updated, reason, now)
+
+		return outcomeResurfaced
+	}
+
+	return outcomeUpdated
+}
+
+// wake brings the snoozed item back as unread.
+func wake(item *Item) {
+	item.ReadAt = nil
+	unsnooze(item)
+}
+
+// unsnooze clears the snooze of the item, e.g. archived items aren't snoozed anymore.
+func unsnooze(item *Item) {
+	item.SnoozedAt = nil
+	item.SnoozedUntil = nil
+	item.SnoozedUntilAction = ""
+}
+
+func resurface(item *Item, reason string, now time.Time) {
+	item.ReadAt = nil
+	item.ResurfacedAt = &now
+	item.ResurfacedReason = reason
+}
+
+// latestNewAction returns the latest of actions appeared in the timeline of the updated item.
+func latestNewAction(stored, updated *Item, actions []Action) Action {
+	if len(actions) == 0 || stored.Timeline.Equal(updated.Timeline) {
+		return ""
+	}
+
+	var (
+		action Action
+		latest time.Time
+	)
+
+	for _, info := range updated.Timeline {
+		if !slices.Contains(actions, info.Action) || slices.ContainsFunc(stored.Timeline, info.equal) {
+			continue
+		}
+
+		if action == "" || info.CreatedAt.After(latest) {
+			action, latest = info.Action, info.CreatedAt
+		}
+	}
+
+	return action
+}
+
+func convertActions(list []string) []Action {
+	actions := make([]Action, 0, len(list))
+	for _, action := range list {
+		actions = append(actions, Action(action))
+	}
+
+	return actions
+}
@@ -0,0 +1,156 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResurfacePolicy_Reason(t *testing.T) {
+	policy := ResurfacePolicy{
+		Actions: []Action{ProposalVotingStarted, ProposalVotingQuorumReached},
+		States:  []string{ProposalStateActive, ProposalStateSucceeded},
+	}
+
+	created := time.Now().Add(-time.Hour)
+	stored := Item{
+		Snapshot: storeTestSnapshot(ProposalStatePending, 1),
+		Timeline: Timeline{{CreatedAt: created, Action: ProposalCreated}},
+	}
+
+	for name, tc := range map[string]struct {
+		snapshot string
+		timeline Timeline
+		expected string
+	}{
+		"same item": {
+			snapshot: ProposalStatePending,
+			timeline: Timeline{{CreatedAt: created.UTC(), Action: ProposalCreated}},
+		},
+		"not significant action": {
+			snapshot: ProposalStatePending,
+			timeline: Timeline{{CreatedAt: created, Action: ProposalCreated}, {CreatedAt: created, Action: ProposalVotingEndsSoon}},
+		},
+		"significant action": {
+			snapshot: ProposalStatePending,
+			timeline: Timeline{{CreatedAt: created, Action: ProposalCreated}, {CreatedAt: created, Action: ProposalVotingQuorumReached}},
+			expected: string(ProposalVotingQuorumReached),
+		},
+		"latest significant action": {
+			snapshot: ProposalStateActive,
+			timeline: Timeline{
+				{CreatedAt: created, Action: ProposalCreated},
+				{CreatedAt: created.Add(2 * time.Minute), Action: ProposalVotingQuorumReached},
+				{CreatedAt: created.Add(time.Minute), Action: ProposalVotingStarted},
+			},
+			expected: string(ProposalVotingQuorumReached),
+		},
+		"significant state": {
+			snapshot: ProposalStateActive,
+			timeline: stored.Timeline,
+			expected: "state:" + ProposalStateActive,
+		},
+		"not significant state": {
+			snapshot: ProposalStateCanceled,
+			timeline: stored.Timeline,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			updated := Item{
+				Snapshot: storeTestSnapshot(tc.snapshot, 1),
+				Timeline: tc.timeline,
+			}
+
+			assert.Equal(t, tc.expected, policy.Reason(&stored, &updated))
+		})
+	}
+}
+
+func TestUpdatePolicy_Apply(t *testing.T) {
+	now := time.Now()
+	archived := now.Add(-time.Hour)
+	endsSoon := Timeline{{CreatedAt: now, Action: ProposalVotingEndsSoon}}
+	quorum := Timeline{{CreatedAt: now, Action: ProposalVotingQuorumReached}}
+
+	for name, tc := range map[string]struct {
+		mode     ArchivedUpdateMode
+		stored   Item
+		timeline Timeline
+		expected updateOutcome
+		archived bool
+		read     bool
+		snoozed  bool
+	}{
+		"updated": {
+			stored:   Item{ReadAt: &archived},
+			expected: outcomeUpdated,
+			read:     true,
+		},
+		"resurfaced": {
+			stored:   Item{ReadAt: &archived},
+			timeline: quorum,
+			expected: outcomeResurfaced,
+		},
+		"unread isn't resurfaced": {
+			stored:   Item{},
+			timeline: quorum,
+			expected: outcomeUpdated,
+		},
+		"frozen": {
+			mode:     ArchivedUpdateFreeze,
+			stored:   Item{ReadAt: &archived, ArchivedAt: &archived, ArchiveReason: ArchivedManually},
+			timeline: quorum,
+			expected: outcomeFrozen,
+			archived: true,
+			read:     true,
+		},
+		"silently updated": {
+			mode:     ArchivedUpdateSilent,
+			stored:   Item{ReadAt: &archived, ArchivedAt: &archived, ArchiveReason: ArchivedManually},
+			timeline: quorum,
+			expected: outcomeUpdated,
+			archived: true,
+			read:     true,
+		},
+		"unarchived": {
+			mode:     ArchivedUpdateFreeze,
+			stored:   Item{ReadAt: &archived, ArchivedAt: &archived, ArchiveReason: ArchivedManually},
+			timeline: endsSoon,
+			expected: outcomeUnarchived,
+		},
+		"voted": {
+			mode:     ArchivedUpdateSilent,
+			stored:   Item{ReadAt: &archived, ArchivedAt: &archived, ArchiveReason: ArchivedManually, VotedAt: &archived},
+			timeline: endsSoon,
+			expected: outcomeUpdated,
+			archived: true,
+			read:     true,
+		},
+		"woken": {
+			stored:   Item{ReadAt: &archived, SnoozedAt: &archived, SnoozedUntilAction: ProposalVotingEndsSoon},
+			timeline: endsSoon,
+			expected: outcomeWoken,
+		},
+		"resurfaced while snoozed": {
+			stored:   Item{ReadAt: &archived, SnoozedAt: &archived, SnoozedUntilAction: ProposalVotingEndsSoon},
+			timeline: quorum,
+			expected: outcomeResurfaced,
+			snoozed:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy := UpdatePolicy{
+				Resurface: ResurfacePolicy{Actions: []Action{ProposalVotingQuorumReached}},
+				Archived:  ArchivedPolicy{Mode: tc.mode, UnarchiveActions: []Action{ProposalVotingEndsSoon}},
+			}
+			updated := Item{Timeline: tc.timeline}
+
+			assert.Equal(t, tc.expected, policy.apply(&tc.stored, &updated, now))
+			assert.Equal(t, tc.archived, updated.ArchivedAt != nil)
+			assert.Equal(t, tc.archived, updated.ArchiveReason != "")
+			assert.Equal(t, tc.read, updated.ReadAt != nil)
+			assert.Equal(t, tc.snoozed, updated.SnoozedAt != nil)
+		})
+	}
+}
@@ -0,0 +1,732 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+	"github.com/goverland-labs/goverland-inbox-feed/protobuf/feedapi"
+)
+
+const (
+	// maxBulkSubscribeDaos limits daos of the single bulk subscribe, proposals of them are stored by the single batch
+	maxBulkSubscribeDaos = 100
+)
+
+type UserFeedServer struct {
+	feedapi.UnimplementedUserFeedServer
+
+	service *Service
+	hub     *Hub
+}
+
+func NewUserFeedServer(service *Service, hub *Hub) *UserFeedServer {
+	return &UserFeedServer{
+		service: service,
+		hub:     hub,
+	}
+}
+
+func (s *UserFeedServer) GetUserFeed(ctx context.Context, req *feedapi.GetUserFeedRequest) (*feedapi.FeedPage, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	var pageFilters []Filter
+	if req.GetCursor() != "" {
+		cursor, err := DecodeCursor(req.GetCursor())
+		if err != nil {
+			log.Warn().Err(err).Str("cursor", req.GetCursor()).Msg("unable to decode cursor")
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+
+		pageFilters = append(pageFilters, FilterAfterCursor(cursor))
+	}
+
+	pageLimit := defaultPageLimit
+	if req.GetLimit() > 0 {
+		pageLimit = min(int(req.GetLimit()), maxPageLimit)
+	}
+	// fetch one extra item to find out if the next page exists
+	pageFilters = append(pageFilters, WithLimit(pageLimit+1, 0))
+
+	viewFilters, err := feedViewFilters(req)
+	if errors.Is(err, errUnknownArchiveReason) {
+		log.Warn().Err(err).Strs("archive_reasons", req.GetArchiveReasons()).Msg("invalid feed view filters")
+		return nil, status.Error(codes.InvalidArgument, "invalid archive reason")
+	}
+	if err != nil {
+		log.Warn().Err(err).Strs("dao_ids", req.GetDaoIds()).Msg("invalid feed view filters")
+		return nil, status.Error(codes.InvalidArgument, "invalid dao id format")
+	}
+
+	filters, unreadStateFilters := userFeedFilters(SortedByActuality(), req.GetReadState(), req.GetArchivedState())
+	filters = append(filters, viewFilters...)
+	filters = append(filters, snoozedFilters(req.GetSnoozed())...)
+
+	var (
+		totalCount, unreadCount int64
+		materialized            bool
+	)
+	// materialized counters don't include snoozed items
+	if len(viewFilters) == 0 && req.GetSnoozed() == feedapi.GetUserFeedRequest_Hidden {
+		counters, err := s.service.GetCounters(ctx, subscriberID)
+		if err != nil {
+			log.Error().Err(err).Msg("unable to get feed counters")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+
+		totalCount, unreadCount, materialized = materializedCounts(counters, req.GetReadState(), req.GetArchivedState())
+	}
+
+	if !materialized {
+		totalCount, err = s.service.CountByFilters(ctx, subscriberID, slices.Concat(filters, unreadStateFilters))
+		if err != nil {
+			log.Error().Err(err).Msg("unable to get total count of feed events")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+
+		unreadCount, err = s.service.CountByFilters(ctx, subscriberID, slices.Concat(filters, []Filter{FilterByReadStatus(helpers.Ptr(false))}))
+		if err != nil {
+			log.Error().Err(err).Msg("unable to get unread count of feed events")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+	}
+
+	list, err := s.service.FindByFilters(ctx, subscriberID, slices.Concat(filters, unreadStateFilters, pageFilters))
+	if err != nil {
+		log.Error().Err(err).Msg("unable to get user feed")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	var nextCursor string
+	if len(list) > pageLimit {
+		list = list[:pageLimit]
+
+		cursor, err := NewCursor(list[pageLimit-1])
+		if err != nil {
+			log.Error().Err(err).Str("feed_id", list[pageLimit-1].ID.String()).Msg("unable to build cursor")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+
+		nextCursor = cursor.Encode()
+	}
+
+	return &feedapi.FeedPage{
+		List:              convertToProto(list),
+		TotalCount:        uint32(totalCount),
+		UnreadCount:       uint32(unreadCount),
+		NextCursor:        nextCursor,
+		ResurfacedReasons: resurfacedReasons(list),
+		ArchiveReasons:    archiveReasons(list),
+	}, nil
+}
+
+// resurfacedReasons returns reasons of resurfaced items by their ids.
+func resurfacedReasons(list []Item) map[string]string {
+	reasons := make(map[string]string)
+	for _, item := range list {
+		if item.ResurfacedReason != "" {
+			reasons[item.ID.String()] = item.ResurfacedReason
+		}
+	}
+
+	return reasons
+}
+
+// archiveReasons returns reasons of archived items by their ids.
+func archiveReasons(list []Item) map[string]string {
+	reasons := make(map[string]string)
+	for _, item := range list {
+		if item.ArchivedAt != nil && item.ArchiveReason != "" {
+			reasons[item.ID.String()] = string(item.ArchiveReason)
+		}
+	}
+
+	return reasons
+}
+
+// materializedCounts returns the same counts as filters built by userFeedFilters match if they could be taken
+// from materialized counters. Unread archived items aren't counted there, so only not archived items are supported.
+func materializedCounts(c Counters, readState, archivedState inboxapi.GetUserFeedRequest_State) (total, unread int64, ok bool) {
+	if archivedState != inboxapi.GetUserFeedRequest_Exclude {
+		return 0, 0, false
+	}
+
+	switch readState {
+	case inboxapi.GetUserFeedRequest_Exclude:
+		return c.UnreadCount, c.UnreadCount, true
+	case inboxapi.GetUserFeedRequest_ExcludeOther:
+		return c.TotalCount - c.UnreadCount, c.UnreadCount, true
+	default:
+		return c.TotalCount, c.UnreadCount, true
+	}
+}
+
+var errUnknownArchiveReason = errors.New("unknown archive reason")
+
+// filterArchiveReasons are the reasons the feed can be filtered by
+var filterArchiveReasons = map[string]ArchiveReason{
+	string(ArchivedManually):     ArchivedManually,
+	string(ArchivedVoted):        ArchivedVoted,
+	string(ArchivedExpired):      ArchivedExpired,
+	string(ArchivedBulkByTime):   ArchivedBulkByTime,
+	string(ArchivedUnsubscribed): ArchivedUnsubscribed,
+}
+
+// feedViewFilters converts optional filters of the request, they are applied to the list and counters.
+func feedViewFilters(req *feedapi.GetUserFeedRequest) ([]Filter, error) {
+	var filters []Filter
+
+	if len(req.GetDaoIds()) > 0 {
+		daoIDs, err := helpers.ConvertStringsToUUIDs(req.GetDaoIds())
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, FilterByDaoIDs(daoIDs...))
+	}
+
+	if len(req.GetActions()) > 0 {
+		actions := make([]Action, 0, len(req.GetActions()))
+		for _, action := range req.GetActions() {
+			actions = append(actions, Action(action))
+		}
+
+		filters = append(filters, FilterByActions(actions...))
+	}
+
+	if req.GetType() != "" {
+		filters = append(filters, FilterByType(Type(req.GetType())))
+	}
+
+	if len(req.GetProposalStates()) > 0 {
+		filters = append(filters, FilterByProposalStates(req.GetProposalStates()...))
+	}
+
+	if len(req.GetArchiveReasons()) > 0 {
+		reasons := make([]ArchiveReason, 0, len(req.GetArchiveReasons()))
+		for _, raw := range req.GetArchiveReasons() {
+			reason, ok := filterArchiveReasons[raw]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", errUnknownArchiveReason, raw)
+			}
+
+			reasons = append(reasons, reason)
+		}
+
+		filters = append(filters, FilterByArchiveReasons(reasons...))
+	}
+
+	switch req.GetDiscussionState() {
+	case inboxapi.GetUserFeedRequest_Exclude:
+		filters = append(filters, SkipDiscussions())
+	case inboxapi.GetUserFeedRequest_ExcludeOther:
+		filters = append(filters, FilterByType(Discussion))
+	default:
+		// discussions are included by default
+	}
+
+	return filters, nil
+}
+
+// snoozedFilters returns filters of snoozed items, unlike other view filters they hide snoozed items by default.
+func snoozedFilters(view feedapi.GetUserFeedRequest_Snoozed) []Filter {
+	switch view {
+	case feedapi.GetUserFeedRequest_Included:
+		return nil
+	case feedapi.GetUserFeedRequest_Only:
+		return []Filter{FilterBySnoozedStatus(helpers.Ptr(true))}
+	default:
+		return []Filter{FilterBySnoozedStatus(helpers.Ptr(false))}
+	}
+}
+
+func (s *UserFeedServer) GetDaoCounters(ctx context.Context, req *feedapi.GetDaoCountersRequest) (*feedapi.DaoCountersList, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	counters, err := s.service.CountersByDao(ctx, subscriberID)
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to get dao counters")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	list := make([]*feedapi.DaoCounters, 0, len(counters))
+	for _, dc := range counters {
+		list = append(list, &feedapi.DaoCounters{
+			DaoId:       dc.DaoID.String(),
+			TotalCount:  uint32(dc.TotalCount),
+			UnreadCount: uint32(dc.UnreadCount),
+		})
+	}
+
+	return &feedapi.DaoCountersList{List: list}, nil
+}
+
+func (s *UserFeedServer) UserUnsubscribe(ctx context.Context, req *feedapi.UserUnsubscribeRequest) (*emptypb.Empty, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	daoID, err := uuid.Parse(req.GetDaoId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid dao id")
+	}
+
+	mode, ok := unsubscribeModes[req.GetMode()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid mode")
+	}
+
+	if err = s.service.Unsubscribe(ctx, subscriberID, SourceGRPC, daoID, mode); err != nil {
+		log.Error().Err(err).Msgf("unsubscribe %s from %s", subscriberID.String(), daoID.String())
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+var unsubscribeModes = map[feedapi.UserUnsubscribeRequest_Mode]UnsubscribeMode{
+	feedapi.UserUnsubscribeRequest_Default: DefaultUnsubscribeMode,
+	feedapi.UserUnsubscribeRequest_Purge:   UnsubscribePurge,
+	feedapi.UserUnsubscribeRequest_Archive: UnsubscribeArchive,
+	feedapi.UserUnsubscribeRequest_Freeze:  UnsubscribeFreeze,
+}
+
+func (s *UserFeedServer) GetBackfillStatus(ctx context.Context, req *feedapi.GetBackfillStatusRequest) (*feedapi.BackfillStatusList, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	var daoID uuid.UUID
+	if req.GetDaoId() != "" {
+		if daoID, err = uuid.Parse(req.GetDaoId()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid dao id")
+		}
+	}
+
+	jobs, err := s.service.BackfillStatus(ctx, subscriberID, daoID)
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to get backfill status")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	list := make([]*feedapi.BackfillStatus, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, convertBackfillJobToAPI(job))
+	}
+
+	return &feedapi.BackfillStatusList{List: list}, nil
+}
+
+func (s *UserFeedServer) UserBulkSubscribe(ctx context.Context, req *feedapi.UserBulkSubscribeRequest) (*feedapi.BackfillStatusList, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	if len(req.GetDaoIds()) == 0 || len(req.GetDaoIds()) > maxBulkSubscribeDaos {
+		return nil, status.Errorf(codes.InvalidArgument, "number of daos must be from 1 to %d", maxBulkSubscribeDaos)
+	}
+
+	daoIDs := make([]uuid.UUID, 0, len(req.GetDaoIds()))
+	for _, id := range req.GetDaoIds() {
+		daoID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid dao id")
+		}
+
+		daoIDs = append(daoIDs, daoID)
+	}
+
+	jobs, err := s.service.BulkSubscribe(ctx, subscriberID, daoIDs)
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to bulk subscribe")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	list := make([]*feedapi.BackfillStatus, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, convertBackfillJobToAPI(job))
+	}
+
+	return &feedapi.BackfillStatusList{List: list}, nil
+}
+
+func (s *UserFeedServer) GetFeedSettings(ctx context.Context, req *feedapi.GetFeedSettingsRequest) (*feedapi.FeedSettings, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	set, err := s.service.FeedSettings(ctx, subscriberID)
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to get feed settings")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	return convertSettingsToAPI(set), nil
+}
+
+func (s *UserFeedServer) UpdateFeedSettings(ctx context.Context, req *feedapi.UpdateFeedSettingsRequest) (*feedapi.FeedSettings, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	// rules are validated before any change, so the invalid request doesn't change settings partially
+	var rules AutoArchiveRules
+	if req.GetAutoarchiveRules() != nil {
+		if rules, err = convertAutoArchiveRulesFromAPI(req.GetAutoarchiveRules().GetList()); err == nil {
+			err = rules.Validate()
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("subscriber_id", subscriberID.String()).Msg("invalid auto-archive rules")
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if req.DaoEvents != nil {
+		if err = s.service.SetDaoEvents(ctx, subscriberID, req.GetDaoEvents()); err != nil {
+			log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to set dao events")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+	}
+
+	if req.GetAutoarchiveRules() != nil {
+		if err = s.service.SetAutoArchiveRules(ctx, subscriberID, rules); err != nil {
+			log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to set auto-archive rules")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+	}
+
+	set, err := s.service.FeedSettings(ctx, subscriberID)
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to get feed settings")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	return convertSettingsToAPI(set), nil
+}
+
+var autoArchiveRuleKinds = map[feedapi.AutoArchiveRule_Kind]AutoArchiveRuleKind{
+	feedapi.AutoArchiveRule_AfterEnd:     AutoArchiveAfterEnd,
+	feedapi.AutoArchiveRule_ReadAndEnded: AutoArchiveReadAndEnded,
+	feedapi.AutoArchiveRule_States:       AutoArchiveStates,
+	feedapi.AutoArchiveRule_NeverDao:     AutoArchiveNeverDao,
+}
+
+func convertAutoArchiveRulesFromAPI(list []*feedapi.AutoArchiveRule) (AutoArchiveRules, error) {
+	rules := make(AutoArchiveRules, 0, len(list))
+	for _, rule := range list {
+		kind, ok := autoArchiveRuleKinds[rule.GetKind()]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown kind %s", ErrInvalidAutoArchiveRule, rule.GetKind())
+		}
+
+		daoIDs, err := helpers.ConvertStringsToUUIDs(rule.GetDaoIds())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAutoArchiveRule, err)
+		}
+
+		rules = append(rules, AutoArchiveRule{
+			Kind:   kind,
+			Days:   int(rule.GetDays()),
+			States: rule.GetStates(),
+			DaoIDs: daoIDs,
+		})
+	}
+
+	return rules, nil
+}
+
+func convertAutoArchiveRulesToAPI(rules AutoArchiveRules) []*feedapi.AutoArchiveRule {
+	list := make([]*feedapi.AutoArchiveRule, 0, len(rules))
+	for _, rule := range rules {
+		converted := &feedapi.AutoArchiveRule{
+			Days:   uint32(rule.Days),
+			States: rule.States,
+		}
+		for kind, internal := range autoArchiveRuleKinds {
+			if internal == rule.Kind {
+				converted.Kind = kind
+			}
+		}
+		for _, daoID := range rule.DaoIDs {
+			converted.DaoIds = append(converted.DaoIds, daoID.String())
+		}
+
+		list = append(list, converted)
+	}
+
+	return list
+}
+
+func (s *UserFeedServer) SnoozeItems(ctx context.Context, req *feedapi.SnoozeItemsRequest) (*emptypb.Empty, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	ids, err := helpers.ConvertStringsToUUIDs(req.GetIds())
+	if err != nil || len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id format")
+	}
+
+	snooze := Snooze{UntilAction: Action(req.GetUntilAction())}
+	if req.GetUntil() != nil {
+		snooze.Until = helpers.Ptr(req.GetUntil().AsTime())
+	}
+
+	err = s.service.Snooze(ctx, subscriberID, snooze, ids...)
+	if errors.Is(err, ErrInvalidSnooze) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to snooze feed items")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserFeedServer) UnsnoozeItems(ctx context.Context, req *feedapi.UnsnoozeItemsRequest) (*emptypb.Empty, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	ids, err := helpers.ConvertStringsToUUIDs(req.GetIds())
+	if err != nil || len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id format")
+	}
+
+	if err = s.service.Unsnooze(ctx, subscriberID, ids...); err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to unsnooze feed items")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserFeedServer) GetItemHistory(ctx context.Context, req *feedapi.GetItemHistoryRequest) (*feedapi.ItemHistoryPage, error) {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	var itemID uuid.UUID
+	if req.GetItemId() != "" {
+		if itemID, err = uuid.Parse(req.GetItemId()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid item id")
+		}
+	}
+
+	var before int64
+	if req.GetCursor() != "" {
+		if before, err = DecodeHistoryCursor(req.GetCursor()); err != nil {
+			log.Warn().Err(err).Str("cursor", req.GetCursor()).Msg("unable to decode history cursor")
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+	}
+
+	pageLimit := defaultPageLimit
+	if req.GetLimit() > 0 {
+		pageLimit = min(int(req.GetLimit()), maxPageLimit)
+	}
+
+	// fetch one extra event to find out if the next page exists
+	events, err := s.service.ItemHistory(ctx, subscriberID, itemID, before, pageLimit+1)
+	if err != nil {
+		log.Error().Err(err).Str("subscriber_id", subscriberID.String()).Msg("unable to get item history")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	var nextCursor string
+	if len(events) > pageLimit {
+		events = events[:pageLimit]
+		nextCursor = EncodeHistoryCursor(events[pageLimit-1])
+	}
+
+	list := make([]*feedapi.ItemHistoryEvent, 0, len(events))
+	for _, event := range events {
+		list = append(list, convertItemHistoryEventToAPI(event))
+	}
+
+	return &feedapi.ItemHistoryPage{
+		List:       list,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func convertItemHistoryEventToAPI(event ItemHistoryEvent) *feedapi.ItemHistoryEvent {
+	return &feedapi.ItemHistoryEvent{
+		ItemId:        event.ItemID.String(),
+		Transition:    string(event.Transition),
+		Source:        string(event.Source),
+		ArchiveReason: string(event.ArchiveReason),
+		OccurredAt:    timestamppb.New(event.OccurredAt),
+	}
+}
+
+func convertSettingsToAPI(set Settings) *feedapi.FeedSettings {
+	return &feedapi.FeedSettings{
+		DaoEvents:            set.DaoEvents,
+		AutoarchiveAfterDays: uint32(set.AutoarchiveAfterDays),
+		AutoarchiveRules:     convertAutoArchiveRulesToAPI(set.EffectiveAutoarchiveRules()),
+	}
+}
+
+var backfillStatuses = map[BackfillStatus]feedapi.BackfillStatus_Status{
+	BackfillPending:   feedapi.BackfillStatus_Pending,
+	BackfillRunning:   feedapi.BackfillStatus_Running,
+	BackfillCompleted: feedapi.BackfillStatus_Completed,
+	BackfillFailed:    feedapi.BackfillStatus_Failed,
+	BackfillCanceled:  feedapi.BackfillStatus_Canceled,
+}
+
+func convertBackfillJobToAPI(job BackfillJob) *feedapi.BackfillStatus {
+	converted := &feedapi.BackfillStatus{
+		DaoId:     job.DaoID.String(),
+		Status:    backfillStatuses[job.Status],
+		Processed: uint32(job.Processed),
+		Total:     uint32(job.Total),
+		Attempts:  uint32(job.Attempts),
+		Error:     job.Error,
+		NextRunAt: timestamppb.New(job.NextRunAt),
+		UpdatedAt: timestamppb.New(job.UpdatedAt),
+	}
+	if job.FinishedAt != nil {
+		converted.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+
+	return converted
+}
+
+func (s *UserFeedServer) WatchUserFeed(req *feedapi.WatchUserFeedRequest, stream feedapi.UserFeed_WatchUserFeedServer) error {
+	subscriberID, err := uuid.Parse(req.GetSubscriberId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid subscriber id")
+	}
+
+	w, replay, reset, err := s.hub.Watch(subscriberID, req.GetResumeToken())
+	if errors.Is(err, ErrInvalidResumeToken) {
+		log.Warn().Err(err).Str("resume_token", req.GetResumeToken()).Msg("unable to parse resume token")
+		return status.Error(codes.InvalidArgument, "invalid resume token")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("unable to watch user feed")
+		return status.Error(codes.Internal, "something went wrong")
+	}
+	defer w.Close()
+
+	ctx := stream.Context()
+	stats, err := s.stats(ctx, subscriberID)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to calc counters")
+		return status.Error(codes.Internal, "something went wrong")
+	}
+
+	// the client token stays valid while the changes after it are replayed
+	resumeToken := req.GetResumeToken()
+	if resumeToken == "" || reset {
+		resumeToken = s.hub.ResumeToken(w.StartSeq())
+	}
+
+	err = stream.Send(&feedapi.FeedUpdate{
+		ResumeToken: resumeToken,
+		Stats:       stats,
+		StatsDelta:  &feedapi.StatsDelta{},
+		Refresh:     reset,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, change := range replay {
+		if stats, err = s.sendChange(ctx, stream, change, stats); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-w.Changes():
+			if !ok {
+				log.Warn().Str("subscriber_id", subscriberID.String()).Bool("lagged", w.Lagged()).Msg("feed watcher detached")
+				return status.Error(codes.ResourceExhausted, "watcher is too slow, resume the stream")
+			}
+
+			if stats, err = s.sendChange(ctx, stream, change, stats); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendChange sends changed items with actual counters and returns these counters.
+func (s *UserFeedServer) sendChange(ctx context.Context, stream feedapi.UserFeed_WatchUserFeedServer, change FeedChange, prev *inboxapi.UnreadStats) (*inboxapi.UnreadStats, error) {
+	var list []Item
+	if !change.Bulk && len(change.ItemIDs) > 0 {
+		var err error
+		list, err = s.service.FindByFilters(ctx, change.SubscriberID, []Filter{FilterByIDs(change.ItemIDs...)})
+		if err != nil {
+			log.Error().Err(err).Msg("unable to get changed feed items")
+			return nil, status.Error(codes.Internal, "something went wrong")
+		}
+	}
+
+	stats, err := s.stats(ctx, change.SubscriberID)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to calc counters")
+		return nil, status.Error(codes.Internal, "something went wrong")
+	}
+
+	err = stream.Send(&feedapi.FeedUpdate{
+		ResumeToken: s.hub.ResumeToken(change.Seq),
+		Items:       convertToProto(list),
+		Stats:       stats,
+		StatsDelta: &feedapi.StatsDelta{
+			TotalCount:  int32(stats.GetTotalCount()) - int32(prev.GetTotalCount()),
+			UnreadCount: int32(stats.GetUnreadCount()) - int32(prev.GetUnreadCount()),
+		},
+		Refresh:           change.Bulk,
+		ResurfacedReasons: resurfacedReasons(list),
+		ArchiveReasons:    archiveReasons(list),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (s *UserFeedServer) stats(ctx context.Context, subscriberID uuid.UUID) (*inboxapi.UnreadStats, error) {
+	total, unread, err := s.service.Counters(ctx, subscriberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inboxapi.UnreadStats{
+		TotalCount:  uint32(total),
+		UnreadCount: uint32(unread),
+	}, nil
+}
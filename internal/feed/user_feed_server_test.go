@@ -0,0 +1,153 @@
+package feed
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/helpers"
+	"github.com/goverland-labs/goverland-inbox-feed/protobuf/feedapi"
+)
+
+func TestMaterializedCounts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	subscriber := uuid.New()
+	items := storeTestItems(t, store, subscriber, 5)
+	require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, items[0].ID, items[1].ID))
+	require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID, items[2].ID))
+
+	counters, err := store.GetCounters(ctx, subscriber)
+	require.NoError(t, err)
+
+	states := []inboxapi.GetUserFeedRequest_State{
+		inboxapi.GetUserFeedRequest_Include,
+		inboxapi.GetUserFeedRequest_Exclude,
+		inboxapi.GetUserFeedRequest_ExcludeOther,
+	}
+
+	for _, readState := range states {
+		for _, archivedState := range states {
+			t.Run(readState.String()+"/"+archivedState.String(), func(t *testing.T) {
+				total, unread, ok := materializedCounts(counters, readState, archivedState)
+				if !ok {
+					assert.NotEqual(t, inboxapi.GetUserFeedRequest_Exclude, archivedState)
+					return
+				}
+
+				filters, unreadStateFilters := userFeedFilters(SortedByActuality(), readState, archivedState)
+				filters = append(filters, FilterBySubscriberID(subscriber))
+
+				expectedTotal, err := store.CountByFilters(ctx, slices.Concat(filters, unreadStateFilters))
+				require.NoError(t, err)
+				expectedUnread, err := store.CountByFilters(ctx, slices.Concat(filters, []Filter{FilterByReadStatus(helpers.Ptr(false))}))
+				require.NoError(t, err)
+
+				assert.Equal(t, expectedTotal, total)
+				assert.Equal(t, expectedUnread, unread)
+			})
+		}
+	}
+}
+
+func TestConvertAutoArchiveRules(t *testing.T) {
+	rules := AutoArchiveRules{
+		{Kind: AutoArchiveAfterEnd, Days: 3},
+		{Kind: AutoArchiveReadAndEnded},
+		{Kind: AutoArchiveStates, States: []string{ProposalStateFailed, ProposalStateDefeated}},
+		{Kind: AutoArchiveNeverDao, DaoIDs: []uuid.UUID{uuid.New()}},
+	}
+
+	converted, err := convertAutoArchiveRulesFromAPI(convertAutoArchiveRulesToAPI(rules))
+	require.NoError(t, err)
+	require.Len(t, converted, len(rules))
+	for idx, rule := range rules {
+		assert.Equal(t, rule.Kind, converted[idx].Kind)
+		assert.Equal(t, rule.Days, converted[idx].Days)
+		assert.Equal(t, rule.States, converted[idx].States)
+		assert.ElementsMatch(t, rule.DaoIDs, converted[idx].DaoIDs)
+	}
+
+	_, err = convertAutoArchiveRulesFromAPI([]*feedapi.AutoArchiveRule{{Kind: feedapi.AutoArchiveRule_NeverDao, DaoIds: []string{"dao"}}})
+	assert.ErrorIs(t, err, ErrInvalidAutoArchiveRule)
+}
+
+func TestUserFeedServer_GetUserFeedArchiveReasons(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	server := NewUserFeedServer(NewService(store, nil, nil, nil), NewHub())
+	subscriber := uuid.New()
+	items := storeTestItems(t, store, subscriber, 2)
+	require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceVoteConsumer, ArchivedVoted, items[0].ID))
+	require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceGRPC, ArchivedManually, items[1].ID))
+
+	page, err := server.GetUserFeed(ctx, &feedapi.GetUserFeedRequest{
+		SubscriberId:   subscriber.String(),
+		ArchivedState:  inboxapi.GetUserFeedRequest_ExcludeOther,
+		ArchiveReasons: []string{string(ArchivedVoted)},
+	})
+	require.NoError(t, err)
+	require.Len(t, page.GetList(), 1)
+	assert.Equal(t, items[0].ID.String(), page.GetList()[0].GetId())
+
+	_, err = server.GetUserFeed(ctx, &feedapi.GetUserFeedRequest{
+		SubscriberId:   subscriber.String(),
+		ArchivedState:  inboxapi.GetUserFeedRequest_ExcludeOther,
+		ArchiveReasons: []string{string(ArchivedVoted), "voted-by-mistake"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "archive reason")
+}
+
+func TestUserFeedServer_GetItemHistory(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(UpdatePolicy{})
+	server := NewUserFeedServer(NewService(store, nil, nil, nil), NewHub())
+	subscriber := uuid.New()
+	items := storeTestItems(t, store, subscriber, 3)
+	for _, item := range items {
+		require.NoError(t, store.MarkAsReadByID(ctx, subscriber, SourceGRPC, item.ID))
+	}
+	require.NoError(t, store.MarkAsArchivedByID(ctx, subscriber, SourceVoteConsumer, ArchivedVoted, items[0].ID))
+
+	var (
+		walked []*feedapi.ItemHistoryEvent
+		cursor string
+	)
+	for {
+		page, err := server.GetItemHistory(ctx, &feedapi.GetItemHistoryRequest{
+			SubscriberId: subscriber.String(),
+			Limit:        3,
+			Cursor:       cursor,
+		})
+		require.NoError(t, err)
+		walked = append(walked, page.GetList()...)
+
+		if cursor = page.GetNextCursor(); cursor == "" {
+			break
+		}
+	}
+
+	require.Len(t, walked, 4)
+	assert.Equal(t, items[0].ID.String(), walked[0].GetItemId())
+	assert.Equal(t, string(TransitionArchived), walked[0].GetTransition())
+	assert.Equal(t, string(SourceVoteConsumer), walked[0].GetSource())
+	assert.Equal(t, string(ArchivedVoted), walked[0].GetArchiveReason())
+	assert.Equal(t, items[0].ID.String(), walked[3].GetItemId())
+	assert.Equal(t, string(TransitionRead), walked[3].GetTransition())
+
+	page, err := server.GetItemHistory(ctx, &feedapi.GetItemHistoryRequest{SubscriberId: subscriber.String(), ItemId: items[1].ID.String()})
+	require.NoError(t, err)
+	require.Len(t, page.GetList(), 1)
+	assert.Empty(t, page.GetNextCursor())
+
+	_, err = server.GetItemHistory(ctx, &feedapi.GetItemHistoryRequest{SubscriberId: subscriber.String(), Cursor: "cursor"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
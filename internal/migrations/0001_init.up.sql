@@ -0,0 +1,36 @@
+-- The schema was created by gorm auto migrations before, so every statement must be idempotent.
+
+create table if not exists items
+(
+    id            text not null,
+    subscriber_id text not null,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz,
+    read_at       timestamptz,
+    archived_at   timestamptz,
+    unarchived_at timestamptz,
+    dao_id        text,
+    proposal_id   text,
+    discussion_id text,
+    type          text,
+    action        text,
+    snapshot      jsonb,
+    timeline      jsonb,
+    primary key (id, subscriber_id)
+);
+
+create index if not exists idx_items_deleted_at on items (deleted_at);
+create index if not exists idx_items_read_at on items (read_at);
+create index if not exists idx_items_archived_at on items (archived_at);
+create unique index if not exists feed_item_dao_proposal_uidx on items (subscriber_id, dao_id, proposal_id);
+
+create table if not exists settings
+(
+    subscriber_id          text,
+    created_at             timestamptz,
+    updated_at             timestamptz,
+    autoarchive_after_days bigint
+);
+
+create index if not exists idx_settings_subscriber_id on settings (subscriber_id);
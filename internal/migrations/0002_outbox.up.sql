@@ -0,0 +1,7 @@
+create table outbox
+(
+    id         bigserial primary key,
+    created_at timestamptz not null default now(),
+    subject    text        not null,
+    payload    jsonb       not null
+);
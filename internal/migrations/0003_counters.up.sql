@@ -0,0 +1,20 @@
+create table counters
+(
+    subscriber_id  text primary key,
+    updated_at     timestamptz not null default now(),
+    total_count    bigint      not null default 0,
+    unread_count   bigint      not null default 0,
+    archived_count bigint      not null default 0
+);
+
+-- The same items as countedFilters returns are taken into account.
+insert into counters (subscriber_id, total_count, unread_count, archived_count)
+select subscriber_id,
+       count(*) filter (where archived_at is null),
+       count(*) filter (where archived_at is null and read_at is null),
+       count(*) filter (where archived_at is not null)
+from items
+where deleted_at is null
+  and snapshot ->> 'spam' != 'true'
+  and snapshot ->> 'state' != 'canceled'
+group by subscriber_id;
@@ -0,0 +1,3 @@
+alter table items
+    drop column if exists resurfaced_at,
+    drop column if exists resurfaced_reason;
@@ -0,0 +1,3 @@
+alter table items
+    add column resurfaced_at     timestamptz,
+    add column resurfaced_reason text not null default '';
@@ -0,0 +1,3 @@
+alter table items
+    drop column if exists unarchived_reason,
+    drop column if exists voted_at;
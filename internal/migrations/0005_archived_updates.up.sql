@@ -0,0 +1,8 @@
+alter table items
+    add column unarchived_reason text not null default '',
+    add column voted_at          timestamptz;
+
+-- items could be unarchived only by subscribers before
+update items
+set unarchived_reason = 'manual'
+where unarchived_at is not null;
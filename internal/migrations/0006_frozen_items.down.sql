@@ -0,0 +1,2 @@
+alter table items
+    drop column if exists frozen_at;
@@ -0,0 +1,2 @@
+alter table items
+    add column frozen_at timestamptz;
@@ -0,0 +1,18 @@
+create table backfill_jobs
+(
+    subscriber_id text        not null,
+    dao_id        text        not null,
+    status        text        not null,
+    total         bigint      not null default 0,
+    processed     bigint      not null default 0,
+    attempts      bigint      not null default 0,
+    error         text        not null default '',
+    next_run_at   timestamptz not null,
+    created_at    timestamptz not null,
+    updated_at    timestamptz not null,
+    finished_at   timestamptz,
+    claim         bigint      not null default 0,
+    primary key (subscriber_id, dao_id)
+);
+
+create index idx_backfill_jobs_next_run_at on backfill_jobs (next_run_at) where status in ('pending', 'running');
@@ -0,0 +1,15 @@
+-- destructive: discussion items would violate the previous index, so they are deleted
+-- and the next up doesn't restore them
+delete
+from items
+where type = 'discussion';
+
+create unique index if not exists feed_item_dao_proposal_uidx on items (subscriber_id, dao_id, proposal_id);
+drop index if exists feed_item_proposal_uidx;
+drop index if exists feed_item_discussion_uidx;
+
+alter table items
+    alter column proposal_id drop not null,
+    alter column proposal_id drop default,
+    alter column discussion_id drop not null,
+    alter column discussion_id drop default;
@@ -0,0 +1,18 @@
+-- nulls are distinct in unique indexes, so keys must not be null
+update items
+set proposal_id = coalesce(proposal_id, ''),
+    discussion_id = coalesce(discussion_id, '')
+where proposal_id is null
+   or discussion_id is null;
+
+alter table items
+    alter column proposal_id set default '',
+    alter column proposal_id set not null,
+    alter column discussion_id set default '',
+    alter column discussion_id set not null;
+
+-- proposals could link the discussion later, so they are identified by the proposal id only
+-- and other items by the discussion id
+create unique index feed_item_proposal_uidx on items (subscriber_id, dao_id, proposal_id) where proposal_id <> '';
+create unique index feed_item_discussion_uidx on items (subscriber_id, dao_id, discussion_id) where proposal_id = '';
+drop index if exists feed_item_dao_proposal_uidx;
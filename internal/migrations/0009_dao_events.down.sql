@@ -0,0 +1,2 @@
+alter table settings
+    drop column if exists dao_events;
@@ -0,0 +1,2 @@
+alter table settings
+    add column dao_events boolean not null default false;
@@ -0,0 +1,5 @@
+drop index if exists idx_items_snoozed_until;
+alter table items
+    drop column if exists snoozed_at,
+    drop column if exists snoozed_until,
+    drop column if exists snoozed_until_action;
@@ -0,0 +1,5 @@
+alter table items
+    add column snoozed_at           timestamptz,
+    add column snoozed_until        timestamptz,
+    add column snoozed_until_action text not null default '';
+create index idx_items_snoozed_until on items (snoozed_until) where snoozed_at is not null;
@@ -0,0 +1,4 @@
+drop table if exists job_runs;
+
+alter table settings
+    drop column if exists autoarchive_rules;
@@ -0,0 +1,9 @@
+alter table settings
+    add column autoarchive_rules jsonb not null default '[]';
+
+-- the start of the last run of periodic jobs shared by replicas, e.g. the auto-archive cycle
+create table job_runs
+(
+    name       text primary key,
+    started_at timestamptz not null
+);
@@ -0,0 +1,2 @@
+alter table items
+    add column archive_reason text not null default '';
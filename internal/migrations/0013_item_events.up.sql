@@ -0,0 +1,13 @@
+create table item_events
+(
+    id             bigserial primary key,
+    subscriber_id  text        not null,
+    item_id        text        not null,
+    transition     text        not null,
+    source         text        not null,
+    archive_reason text        not null default '',
+    occurred_at    timestamptz not null
+);
+
+create index idx_item_events_subscriber_id on item_events (subscriber_id, id);
+create index idx_item_events_item_id on item_events (subscriber_id, item_id, id);
@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/migrator"
+)
+
+// Files must be named as <version>_<name>.up.sql and <version>_<name>.down.sql.
+// Applied migrations must never be changed, add the new one instead.
+//
+//go:embed *.sql
+var files embed.FS
+
+func NewMigrator(db *sql.DB) (*migrator.Migrator, error) {
+	return migrator.New(db, files)
+}
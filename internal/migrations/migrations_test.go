@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goverland-labs/goverland-inbox-feed/pkg/migrator"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	list, err := migrator.Load(files)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+
+	for idx, mg := range list {
+		require.Equal(t, int64(idx+1), mg.Version, "migration versions must be sequential")
+	}
+}
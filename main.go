@@ -1,8 +1,11 @@
 package main
 
 import (
+	"os"
+
 	"github.com/caarlos0/env/v8"
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/s-larionov/process-manager"
 
 	"github.com/goverland-labs/goverland-inbox-feed/internal"
@@ -26,6 +29,22 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("migrate")
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "dlq" {
+		if err := runDLQ(os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("dlq")
+		}
+
+		return
+	}
+
 	app, err := internal.NewApplication(cfg)
 	if err != nil {
 		panic(err)
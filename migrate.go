@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/goverland-labs/goverland-inbox-feed/internal/migrations"
+)
+
+const migrateUsage = "usage: migrate [up | down [steps] | status]"
+
+func runMigrate(args []string) error {
+	conn, err := gorm.Open(postgres.Open(cfg.Database.DSN), &gorm.Config{})
+	if err != nil {
+		return err
+	}
+
+	db, err := conn.DB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := migrations.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("init migrator: %w", err)
+	}
+
+	ctx := context.Background()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mg := range applied {
+			log.Info().Int64("version", mg.Version).Str("name", mg.Name).Msg("migration applied")
+		}
+
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps value '%s': %s", args[1], migrateUsage)
+			}
+		}
+
+		rolledBack, err := m.Down(ctx, steps)
+		for _, mg := range rolledBack {
+			log.Info().Int64("version", mg.Version).Str("name", mg.Name).Msg("migration rolled back")
+		}
+
+		return err
+	case "status":
+		applied, waiting, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, am := range applied {
+			log.Info().Int64("version", am.Version).Str("name", am.Name).Time("applied_at", am.AppliedAt).Msg("applied")
+		}
+		for _, mg := range waiting {
+			log.Info().Int64("version", mg.Version).Str("name", mg.Name).Msg("pending")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown command '%s': %s", command, migrateUsage)
+	}
+}
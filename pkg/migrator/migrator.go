@@ -0,0 +1,347 @@
+package migrator
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultTable   = "schema_migrations"
+	defaultLockKey = 7140429181390411421
+)
+
+var (
+	ErrSchemaBehind = errors.New("database schema is behind")
+	ErrNoMigration  = errors.New("no migration to rollback")
+
+	fileNameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type AppliedMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+type Option func(*Migrator)
+
+// WithTable overrides the name of the table that stores applied versions.
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithLockKey overrides the key of the advisory lock. Services sharing the same database must use different keys.
+func WithLockKey(key int64) Option {
+	return func(m *Migrator) {
+		m.lockKey = key
+	}
+}
+
+// Migrator applies ordered SQL migrations to the Postgres database.
+// Every migration runs in its own transaction, the whole run is guarded
+// by the advisory lock, so only one replica migrates at a time.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	table      string
+	lockKey    int64
+}
+
+func New(db *sql.DB, source fs.FS, opts ...Option) (*Migrator, error) {
+	migrations, err := Load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		db:         db,
+		migrations: migrations,
+		table:      defaultTable,
+		lockKey:    defaultLockKey,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+// Load reads migrations from the root of the source. Each migration is the pair of
+// files named as <version>_<name>.up.sql and <version>_<name>.down.sql.
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := fileNameRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("unexpected migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = mg
+		}
+
+		if mg.Name != matches[2] {
+			return nil, fmt.Errorf("duplicated migration version %d: %s and %s", version, mg.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			mg.Up = string(content)
+		} else {
+			mg.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", mg.Version, mg.Name)
+		}
+
+		migrations = append(migrations, *mg)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Up applies all pending migrations and returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range pending(m.migrations, applied) {
+			err = m.inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mg.Up); err != nil {
+					return err
+				}
+
+				_, err := tx.ExecContext(ctx,
+					fmt.Sprintf("insert into %s (version, name) values ($1, $2)", m.table),
+					mg.Version, mg.Name,
+				)
+
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+
+			done = append(done, mg)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down rolls back the given number of the latest applied migrations and returns the rolled back ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(applied) - 1; i >= 0 && len(done) < steps; i-- {
+			idx := slices.IndexFunc(m.migrations, func(mg Migration) bool {
+				return mg.Version == applied[i].Version
+			})
+			if idx == -1 {
+				return fmt.Errorf("%w: unknown version %d", ErrNoMigration, applied[i].Version)
+			}
+
+			mg := m.migrations[idx]
+			err = m.inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mg.Down); err != nil {
+					return err
+				}
+
+				_, err := tx.ExecContext(ctx,
+					fmt.Sprintf("delete from %s where version = $1", m.table),
+					mg.Version,
+				)
+
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("rollback migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+
+			done = append(done, mg)
+		}
+
+		if len(done) == 0 && steps > 0 {
+			return ErrNoMigration
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status returns applied migrations and migrations waiting to be applied.
+func (m *Migrator) Status(ctx context.Context) (applied []AppliedMigration, waiting []Migration, err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	applied, err = m.applied(ctx, conn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return applied, pending(m.migrations, applied), nil
+}
+
+// Check returns ErrSchemaBehind if the database has migrations waiting to be applied.
+func (m *Migrator) Check(ctx context.Context) error {
+	_, waiting, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(waiting) > 0 {
+		return fmt.Errorf("%w: %d migration(s) are not applied, the first one is %d_%s",
+			ErrSchemaBehind, len(waiting), waiting[0].Version, waiting[0].Name)
+	}
+
+	return nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	// advisory lock belongs to the session, so all queries must use the same connection
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "select pg_advisory_lock($1)", m.lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// use the separate context to release the lock even if the main one is canceled
+		if _, err := conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", m.lockKey); err != nil {
+			// discard the connection: closing the session releases the lock as well
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	if _, err = conn.ExecContext(ctx, fmt.Sprintf(`create table if not exists %s (
+		version    bigint primary key,
+		name       text not null,
+		applied_at timestamptz not null default now()
+	)`, m.table)); err != nil {
+		return fmt.Errorf("create migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) ([]AppliedMigration, error) {
+	var exists bool
+	err := conn.QueryRowContext(ctx, "select to_regclass($1) is not null", m.table).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("check migrations table: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("select version, name, applied_at from %s order by version", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("select applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var am AppliedMigration
+		if err = rows.Scan(&am.Version, &am.Name, &am.AppliedAt); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+
+		applied = append(applied, am)
+	}
+
+	return applied, rows.Err()
+}
+
+func pending(migrations []Migration, applied []AppliedMigration) []Migration {
+	var list []Migration
+	for _, mg := range migrations {
+		if !slices.ContainsFunc(applied, func(am AppliedMigration) bool { return am.Version == mg.Version }) {
+			list = append(list, mg)
+		}
+	}
+
+	return list
+}
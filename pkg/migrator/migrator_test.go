@@ -0,0 +1,84 @@
+package migrator
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	for name, tc := range map[string]struct {
+		files    fstest.MapFS
+		versions []int64
+		err      bool
+	}{
+		"empty": {
+			files: fstest.MapFS{},
+		},
+		"sorted by version": {
+			files: fstest.MapFS{
+				"0010_second.up.sql":   {Data: []byte("select 2")},
+				"0010_second.down.sql": {Data: []byte("select -2")},
+				"0002_first.up.sql":    {Data: []byte("select 1")},
+				"0002_first.down.sql":  {Data: []byte("select -1")},
+			},
+			versions: []int64{2, 10},
+		},
+		"missed down file": {
+			files: fstest.MapFS{
+				"0001_first.up.sql": {Data: []byte("select 1")},
+			},
+			err: true,
+		},
+		"duplicated version": {
+			files: fstest.MapFS{
+				"0001_first.up.sql":    {Data: []byte("select 1")},
+				"0001_first.down.sql":  {Data: []byte("select -1")},
+				"0001_second.up.sql":   {Data: []byte("select 2")},
+				"0001_second.down.sql": {Data: []byte("select -2")},
+			},
+			err: true,
+		},
+		"unexpected file name": {
+			files: fstest.MapFS{
+				"first.sql": {Data: []byte("select 1")},
+			},
+			err: true,
+		},
+		"zero version": {
+			files: fstest.MapFS{
+				"0000_first.up.sql":   {Data: []byte("select 1")},
+				"0000_first.down.sql": {Data: []byte("select -1")},
+			},
+			err: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			list, err := Load(tc.files)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			versions := make([]int64, 0, len(list))
+			for _, mg := range list {
+				assert.NotEmpty(t, mg.Up)
+				assert.NotEmpty(t, mg.Down)
+				versions = append(versions, mg.Version)
+			}
+			assert.ElementsMatch(t, tc.versions, versions)
+			assert.IsNonDecreasing(t, versions)
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}}
+	applied := []AppliedMigration{{Version: 1}, {Version: 3}}
+
+	assert.Equal(t, []Migration{{Version: 2}}, pending(migrations, applied))
+	assert.Empty(t, pending(migrations[:1], applied))
+}
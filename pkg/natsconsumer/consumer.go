@@ -0,0 +1,334 @@
+// Package natsconsumer implements the JetStream queue consumer with bounded retries and the dead-letter subject.
+// It's compatible with consumers created by natsclient, so the delivery position of the durable consumer is kept.
+// Replayed dead letters are delivered through the separate replay subject, so other consumers
+// of the original subject don't receive them twice.
+package natsconsumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	client "github.com/goverland-labs/goverland-platform-events/pkg/natsclient"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	actionAck        = "ack"
+	actionNack       = "nack"
+	actionDeadLetter = "dead_letter"
+
+	deadLetterPublishTimeout = 5 * time.Second
+	// ackMargin leaves the time to nack the message before the ack wait is over
+	ackMargin       = time.Second
+	drainCheckDelay = 50 * time.Millisecond
+)
+
+var (
+	ErrGroupRequired             = errors.New("group is required")
+	ErrDeadLetterSubjectRequired = errors.New("dead-letter subject is required")
+)
+
+// Handler processes the raw message data. The context is canceled when the ack wait is over
+// or the consumer is closed and in-flight messages weren't processed in time.
+type Handler func(ctx context.Context, data []byte) error
+
+// JSONHandler decodes the message payload before passing it to the handler.
+// Payloads which can't be decoded are dead-lettered without retries.
+func JSONHandler[T any](h func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, data []byte) error {
+		var payload T
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return Permanent(fmt.Errorf("unmarshal payload: %w", err))
+		}
+
+		return h(ctx, payload)
+	}
+}
+
+type Publisher interface {
+	PublishJSON(ctx context.Context, subject string, obj any) error
+}
+
+type Config struct {
+	Group   string
+	Subject string
+	// DeadLetterSubject receives messages which failed permanently or ran out of retries
+	DeadLetterSubject string
+	Retry             RetryPolicy
+	AckWait           time.Duration
+	MaxAckPending     int
+	RateLimit         uint64
+	// ShutdownTimeout limits the time to process in-flight messages on close, AckWait by default
+	ShutdownTimeout time.Duration
+}
+
+type Consumer struct {
+	subs       []*nats.Subscription
+	cfg        Config
+	handler    Handler
+	deadLetter Publisher
+	// ctx is the parent of handler contexts, it's canceled on close after in-flight messages are drained
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// message is the part of nats.Msg used by the consumer.
+type message interface {
+	Data() []byte
+	NumDelivered() (uint64, error)
+	Ack() error
+	NakWithDelay(delay time.Duration) error
+	Term() error
+}
+
+func New(ctx context.Context, conn *nats.Conn, cfg Config, h Handler, deadLetter Publisher) (*Consumer, error) {
+	if cfg.Group == "" {
+		return nil, ErrGroupRequired
+	}
+
+	if cfg.Subject == "" {
+		return nil, client.ErrSubjectRequired
+	}
+
+	if cfg.DeadLetterSubject == "" {
+		return nil, ErrDeadLetterSubjectRequired
+	}
+
+	if cfg.ShutdownTimeout == 0 {
+		cfg.ShutdownTimeout = cfg.AckWait
+	}
+
+	c := &Consumer{
+		cfg:        cfg,
+		handler:    h,
+		deadLetter: deadLetter,
+	}
+	// in-flight messages are drained on close instead of being interrupted with the parent context
+	c.ctx, c.cancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	for _, subject := range []string{cfg.Subject, ReplaySubject(cfg.DeadLetterSubject, cfg.Subject)} {
+		sub, err := c.subscribe(ctx, conn, subject)
+		if err != nil {
+			c.Close() // nolint:errcheck
+
+			return nil, err
+		}
+
+		c.subs = append(c.subs, sub)
+	}
+
+	return c, nil
+}
+
+func (c *Consumer) subscribe(ctx context.Context, conn *nats.Conn, subject string) (*nats.Subscription, error) {
+	// the producer creates the stream for the subject in the same way as natsclient consumer does
+	if _, err := client.NewProducer(conn, subject); err != nil {
+		return nil, fmt.Errorf("prepare stream: %w", err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	streamName, err := js.StreamNameBySubject(subject)
+	if err != nil {
+		return nil, fmt.Errorf("find stream by subject %s: %w", subject, err)
+	}
+
+	name := buildConsumerName(c.cfg.Group, subject)
+	consumerCfg := &nats.ConsumerConfig{
+		Durable:        name,
+		Name:           name,
+		DeliverPolicy:  nats.DeliverAllPolicy,
+		AckPolicy:      nats.AckExplicitPolicy,
+		DeliverSubject: fmt.Sprintf("deliver.%s", name),
+		DeliverGroup:   c.cfg.Group,
+		FilterSubject:  subject,
+		AckWait:        c.cfg.AckWait,
+		MaxAckPending:  c.cfg.MaxAckPending,
+		RateLimit:      c.cfg.RateLimit,
+		// redeliveries are limited by the retry policy, so the message is never dropped silently
+		MaxDeliver: -1,
+	}
+
+	if _, err = js.ConsumerInfo(streamName, name); errors.Is(err, nats.ErrConsumerNotFound) {
+		_, err = js.AddConsumer(streamName, consumerCfg)
+	} else if err == nil {
+		_, err = js.UpdateConsumer(streamName, consumerCfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create consumer '%s': %w", name, err)
+	}
+
+	sub, err := js.QueueSubscribe(subject, c.cfg.Group, func(msg *nats.Msg) {
+		c.handle(natsMessage{msg})
+	},
+		nats.Bind(streamName, name),
+		nats.ManualAck(),
+		nats.Context(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("queue subscribe %s: %w", subject, err)
+	}
+
+	return sub, nil
+}
+
+func (c *Consumer) handle(msg message) {
+	var (
+		start  = time.Now()
+		action = actionAck
+		err    error
+	)
+
+	defer func() {
+		client.CollectConsumerMetric(c.cfg.Subject, action, err, time.Since(start).Seconds())
+	}()
+
+	ctx, cancel := c.handlerContext()
+	defer cancel()
+
+	if err = c.handler(ctx, msg.Data()); err == nil {
+		if ackErr := msg.Ack(); ackErr != nil {
+			log.Error().Err(ackErr).Str("subject", c.cfg.Subject).Msg("ack message")
+		}
+
+		return
+	}
+
+	delivered, mdErr := msg.NumDelivered()
+	if mdErr != nil {
+		log.Warn().Err(mdErr).Str("subject", c.cfg.Subject).Msg("unable to get message metadata")
+		delivered = 1
+	}
+
+	if !IsPermanent(err) && !c.cfg.Retry.Exhausted(delivered) {
+		action = actionNack
+		log.Warn().Err(err).Str("subject", c.cfg.Subject).Uint64("delivered", delivered).Msg("message will be redelivered")
+
+		if nakErr := msg.NakWithDelay(c.cfg.Retry.Backoff(delivered)); nakErr != nil {
+			log.Error().Err(nakErr).Str("subject", c.cfg.Subject).Msg("nack message")
+		}
+
+		return
+	}
+
+	action = actionDeadLetter
+	log.Error().Err(err).Str("subject", c.cfg.Subject).Uint64("delivered", delivered).Msg("message is dead-lettered")
+
+	if dlErr := c.publishDeadLetter(msg.Data(), err, delivered); dlErr != nil {
+		// keep the message in the stream until the dead letter is stored
+		log.Error().Err(dlErr).Str("subject", c.cfg.Subject).Msg("publish dead letter")
+
+		if nakErr := msg.NakWithDelay(c.cfg.Retry.MaxBackoff); nakErr != nil {
+			log.Error().Err(nakErr).Str("subject", c.cfg.Subject).Msg("nack message")
+		}
+
+		return
+	}
+
+	if termErr := msg.Term(); termErr != nil {
+		log.Error().Err(termErr).Str("subject", c.cfg.Subject).Msg("terminate message")
+	}
+}
+
+func (c *Consumer) handlerContext() (context.Context, context.CancelFunc) {
+	timeout := c.cfg.AckWait
+	if timeout > 2*ackMargin {
+		timeout -= ackMargin
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(c.ctx)
+	}
+
+	return context.WithTimeout(c.ctx, timeout)
+}
+
+func (c *Consumer) publishDeadLetter(data []byte, cause error, delivered uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterPublishTimeout)
+	defer cancel()
+
+	return c.deadLetter.PublishJSON(ctx, c.cfg.DeadLetterSubject, DeadLetter{
+		Subject:   c.cfg.Subject,
+		Group:     c.cfg.Group,
+		Data:      data,
+		Error:     cause.Error(),
+		Delivered: delivered,
+		FailedAt:  time.Now(),
+	})
+}
+
+// Close stops receiving new messages and waits for in-flight ones up to the shutdown timeout.
+// Handlers which are still running after that are canceled.
+func (c *Consumer) Close() error {
+	defer c.cancel()
+
+	var errs []error
+	for _, sub := range c.subs {
+		if err := sub.Drain(); err != nil {
+			errs = append(errs, fmt.Errorf("drain [%s/%s]: %w", sub.Subject, c.cfg.Group, err))
+		}
+	}
+
+	timeout := time.After(c.cfg.ShutdownTimeout)
+	for _, sub := range c.subs {
+		// the subscription becomes invalid when all delivered messages are processed
+		for sub.IsValid() {
+			select {
+			case <-timeout:
+				errs = append(errs, fmt.Errorf("drain [%s/%s]: in-flight messages weren't processed in time", sub.Subject, c.cfg.Group))
+
+				return errors.Join(errs...)
+			case <-time.After(drainCheckDelay):
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReplaySubject returns the subject for replayed dead letters of the original subject.
+func ReplaySubject(deadLetterSubject, subject string) string {
+	return fmt.Sprintf("%s.replay.%s", deadLetterSubject, subject)
+}
+
+// buildConsumerName returns the same name as natsclient does.
+func buildConsumerName(group, subject string) string {
+	return strings.ReplaceAll(fmt.Sprintf("consumer_%s_%s", group, subject), ".", "_")
+}
+
+type natsMessage struct {
+	msg *nats.Msg
+}
+
+func (m natsMessage) Data() []byte {
+	return m.msg.Data
+}
+
+func (m natsMessage) NumDelivered() (uint64, error) {
+	md, err := m.msg.Metadata()
+	if err != nil {
+		return 0, err
+	}
+
+	return md.NumDelivered, nil
+}
+
+func (m natsMessage) Ack() error {
+	return m.msg.AckSync()
+}
+
+func (m natsMessage) NakWithDelay(delay time.Duration) error {
+	return m.msg.NakWithDelay(delay)
+}
+
+func (m natsMessage) Term() error {
+	return m.msg.Term()
+}
@@ -0,0 +1,180 @@
+package natsconsumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMessage struct {
+	data      []byte
+	delivered uint64
+	acked     bool
+	nakDelay  time.Duration
+	termed    bool
+}
+
+func (m *fakeMessage) Data() []byte {
+	return m.data
+}
+
+func (m *fakeMessage) NumDelivered() (uint64, error) {
+	return m.delivered, nil
+}
+
+func (m *fakeMessage) Ack() error {
+	m.acked = true
+	return nil
+}
+
+func (m *fakeMessage) NakWithDelay(delay time.Duration) error {
+	m.nakDelay = delay
+	return nil
+}
+
+func (m *fakeMessage) Term() error {
+	m.termed = true
+	return nil
+}
+
+type fakePublisher struct {
+	subject string
+	letters []DeadLetter
+	err     error
+}
+
+func (p *fakePublisher) PublishJSON(_ context.Context, subject string, obj any) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.subject = subject
+	p.letters = append(p.letters, obj.(DeadLetter))
+
+	return nil
+}
+
+func TestConsumer_Handle(t *testing.T) {
+	type payload struct {
+		ID string `json:"id"`
+	}
+
+	retryable := errors.New("unavailable")
+	handler := JSONHandler(func(_ context.Context, p payload) error {
+		switch p.ID {
+		case "retryable":
+			return retryable
+		case "permanent":
+			return Permanent(errors.New("invalid"))
+		default:
+			return nil
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		data       string
+		delivered  uint64
+		publishErr error
+		acked      bool
+		nakDelay   time.Duration
+		termed     bool
+		deadLetter bool
+	}{
+		"processed": {
+			data:      `{"id":"ok"}`,
+			delivered: 1,
+			acked:     true,
+		},
+		"retryable error": {
+			data:      `{"id":"retryable"}`,
+			delivered: 2,
+			nakDelay:  2 * time.Second,
+		},
+		"retries are exhausted": {
+			data:       `{"id":"retryable"}`,
+			delivered:  3,
+			termed:     true,
+			deadLetter: true,
+		},
+		"permanent error": {
+			data:       `{"id":"permanent"}`,
+			delivered:  1,
+			termed:     true,
+			deadLetter: true,
+		},
+		"malformed payload": {
+			data:       `{"id":`,
+			delivered:  1,
+			termed:     true,
+			deadLetter: true,
+		},
+		"dead-letter subject is unavailable": {
+			data:       `{"id":"permanent"}`,
+			delivered:  1,
+			publishErr: errors.New("unavailable"),
+			nakDelay:   time.Minute,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			publisher := &fakePublisher{err: tc.publishErr}
+			c := &Consumer{
+				ctx: context.Background(),
+				cfg: Config{
+					Group:             "group",
+					Subject:           "subject",
+					DeadLetterSubject: "dlq",
+					Retry:             RetryPolicy{MaxDeliver: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute},
+				},
+				handler:    handler,
+				deadLetter: publisher,
+			}
+
+			msg := &fakeMessage{data: []byte(tc.data), delivered: tc.delivered}
+			c.handle(msg)
+
+			assert.Equal(t, tc.acked, msg.acked)
+			assert.Equal(t, tc.nakDelay, msg.nakDelay)
+			assert.Equal(t, tc.termed, msg.termed)
+
+			if !tc.deadLetter {
+				assert.Empty(t, publisher.letters)
+				return
+			}
+
+			require.Len(t, publisher.letters, 1)
+			assert.Equal(t, "dlq", publisher.subject)
+			assert.Equal(t, "subject", publisher.letters[0].Subject)
+			assert.Equal(t, "group", publisher.letters[0].Group)
+			assert.Equal(t, tc.data, string(publisher.letters[0].Data))
+			assert.Equal(t, tc.delivered, publisher.letters[0].Delivered)
+			assert.NotEmpty(t, publisher.letters[0].Error)
+		})
+	}
+}
+
+func TestReplaySubject(t *testing.T) {
+	assert.Equal(t, "inbox.feed.dlq.replay.inbox.feed.updated", ReplaySubject("inbox.feed.dlq", "inbox.feed.updated"))
+}
+
+func TestConsumer_HandlerContext(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	c := &Consumer{cfg: Config{AckWait: time.Minute}}
+	c.ctx, c.cancel = context.WithCancel(context.WithoutCancel(parent))
+
+	ctx, cancel := c.handlerContext()
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute-ackMargin), deadline, time.Second)
+
+	cancelParent()
+	assert.NoError(t, ctx.Err(), "in-flight messages are not interrupted by the parent context")
+
+	c.cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
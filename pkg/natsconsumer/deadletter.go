@@ -0,0 +1,113 @@
+package natsconsumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	replayConsumerName = "dead_letter_replay"
+	replayBatchSize    = 50
+	replayFetchTimeout = 2 * time.Second
+)
+
+// DeadLetter is the message which wasn't processed by the consumer.
+type DeadLetter struct {
+	// Subject of the original message
+	Subject string `json:"subject"`
+	Group   string `json:"group"`
+	// Data is the original message payload as is
+	Data      []byte    `json:"data"`
+	Error     string    `json:"error"`
+	Delivered uint64    `json:"delivered"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// Replay publishes up to limit dead letters to replay subjects of their original subjects and deletes them
+// from the dead-letter stream, the ack alone doesn't remove messages from the stream with the limits retention.
+// Returns the number of replayed messages.
+func Replay(ctx context.Context, conn *nats.Conn, deadLetterSubject string, limit int) (int, error) {
+	js, err := conn.JetStream()
+	if err != nil {
+		return 0, err
+	}
+
+	sub, err := js.PullSubscribe(deadLetterSubject, replayConsumerName, nats.Context(ctx))
+	if errors.Is(err, nats.ErrNoMatchingStream) {
+		// nothing was dead-lettered yet
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("subscribe to %s: %w", deadLetterSubject, err)
+	}
+	defer sub.Unsubscribe() // nolint:errcheck
+
+	var replayed int
+	for replayed < limit {
+		msgs, err := sub.Fetch(min(replayBatchSize, limit-replayed), nats.MaxWait(replayFetchTimeout))
+		if errors.Is(err, nats.ErrTimeout) {
+			return replayed, nil
+		}
+		if err != nil {
+			return replayed, fmt.Errorf("fetch dead letters: %w", err)
+		}
+
+		for _, msg := range msgs {
+			ok, err := replayMessage(ctx, js, deadLetterSubject, msg)
+			if err != nil {
+				return replayed, err
+			}
+
+			if ok {
+				replayed++
+			}
+		}
+	}
+
+	return replayed, nil
+}
+
+// replayMessage publishes the dead letter to the replay subject, malformed dead letters are dropped.
+func replayMessage(ctx context.Context, js nats.JetStreamContext, deadLetterSubject string, msg *nats.Msg) (bool, error) {
+	var letter DeadLetter
+	if err := json.Unmarshal(msg.Data, &letter); err != nil || letter.Subject == "" {
+		log.Warn().Err(err).Bytes("data", msg.Data).Msg("drop malformed dead letter")
+
+		if err = msg.Term(); err != nil {
+			return false, fmt.Errorf("term dead letter: %w", err)
+		}
+
+		return false, deleteDeadLetter(js, msg)
+	}
+
+	subject := ReplaySubject(deadLetterSubject, letter.Subject)
+	if _, err := js.Publish(subject, letter.Data, nats.Context(ctx)); err != nil {
+		return false, fmt.Errorf("publish to %s: %w", subject, err)
+	}
+
+	if err := msg.AckSync(); err != nil {
+		return false, fmt.Errorf("ack dead letter: %w", err)
+	}
+
+	return true, deleteDeadLetter(js, msg)
+}
+
+// deleteDeadLetter removes the handled dead letter from the stream, so it isn't kept until the stream limits drop it.
+func deleteDeadLetter(js nats.JetStreamContext, msg *nats.Msg) error {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return fmt.Errorf("dead letter metadata: %w", err)
+	}
+
+	if err = js.DeleteMsg(meta.Stream, meta.Sequence.Stream); err != nil {
+		return fmt.Errorf("delete dead letter %d: %w", meta.Sequence.Stream, err)
+	}
+
+	return nil
+}
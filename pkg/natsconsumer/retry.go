@@ -0,0 +1,59 @@
+package natsconsumer
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryPolicy bounds redeliveries of the failed message.
+type RetryPolicy struct {
+	// MaxDeliver is the number of delivery attempts before the message is dead-lettered
+	MaxDeliver int
+	// InitialBackoff is the redelivery delay after the first failed attempt, it's doubled on every next attempt
+	InitialBackoff time.Duration
+	// MaxBackoff limits the redelivery delay
+	MaxBackoff time.Duration
+}
+
+// Backoff returns the redelivery delay after the failed attempt with the given number.
+func (p RetryPolicy) Backoff(delivered uint64) time.Duration {
+	backoff := p.InitialBackoff
+	for i := uint64(1); i < delivered && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, p.MaxBackoff)
+}
+
+// Exhausted reports if the message can't be redelivered after the failed attempt with the given number.
+func (p RetryPolicy) Exhausted(delivered uint64) bool {
+	return delivered >= uint64(p.MaxDeliver)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks the error as not retryable, the message is dead-lettered without redeliveries.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports if the error is not retryable.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+
+	return errors.As(err, &permanent)
+}
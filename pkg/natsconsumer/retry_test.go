@@ -0,0 +1,52 @@
+package natsconsumer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxDeliver: 10, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	for delivered, expected := range map[uint64]time.Duration{
+		0:   time.Second,
+		1:   time.Second,
+		2:   2 * time.Second,
+		3:   4 * time.Second,
+		4:   8 * time.Second,
+		5:   10 * time.Second,
+		100: 10 * time.Second,
+	} {
+		assert.Equal(t, expected, policy.Backoff(delivered), "delivered %d", delivered)
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	policy := RetryPolicy{MaxDeliver: 3}
+
+	assert.False(t, policy.Exhausted(1))
+	assert.False(t, policy.Exhausted(2))
+	assert.True(t, policy.Exhausted(3))
+	assert.True(t, policy.Exhausted(4))
+}
+
+func TestIsPermanent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err       error
+		permanent bool
+	}{
+		"regular":   {err: errors.New("unavailable")},
+		"permanent": {err: Permanent(errors.New("invalid")), permanent: true},
+		"wrapped":   {err: fmt.Errorf("process: %w", Permanent(errors.New("invalid"))), permanent: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.permanent, IsPermanent(tc.err))
+		})
+	}
+
+	assert.NoError(t, Permanent(nil))
+}
@@ -0,0 +1,3 @@
+//go:generate sh -c "cd ../.. && sh bin/compile_proto.sh"
+
+package feedapi
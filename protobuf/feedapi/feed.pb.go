@@ -0,0 +1,2551 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.3
+// source: feedapi/feed.proto
+
+package feedapi
+
+import (
+	inboxapi "github.com/goverland-labs/goverland-inbox-api-protocol/protobuf/inboxapi"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetUserFeedRequest_Snoozed int32
+
+const (
+	GetUserFeedRequest_Hidden   GetUserFeedRequest_Snoozed = 0 // Don't include snoozed items
+	GetUserFeedRequest_Included GetUserFeedRequest_Snoozed = 1 // Include snoozed items
+	GetUserFeedRequest_Only     GetUserFeedRequest_Snoozed = 2 // Return snoozed items ONLY
+)
+
+// Enum value maps for GetUserFeedRequest_Snoozed.
+var (
+	GetUserFeedRequest_Snoozed_name = map[int32]string{
+		0: "Hidden",
+		1: "Included",
+		2: "Only",
+	}
+	GetUserFeedRequest_Snoozed_value = map[string]int32{
+		"Hidden":   0,
+		"Included": 1,
+		"Only":     2,
+	}
+)
+
+func (x GetUserFeedRequest_Snoozed) Enum() *GetUserFeedRequest_Snoozed {
+	p := new(GetUserFeedRequest_Snoozed)
+	*p = x
+	return p
+}
+
+func (x GetUserFeedRequest_Snoozed) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GetUserFeedRequest_Snoozed) Descriptor() protoreflect.EnumDescriptor {
+	return file_feedapi_feed_proto_enumTypes[0].Descriptor()
+}
+
+func (GetUserFeedRequest_Snoozed) Type() protoreflect.EnumType {
+	return &file_feedapi_feed_proto_enumTypes[0]
+}
+
+func (x GetUserFeedRequest_Snoozed) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GetUserFeedRequest_Snoozed.Descriptor instead.
+func (GetUserFeedRequest_Snoozed) EnumDescriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type UserUnsubscribeRequest_Mode int32
+
+const (
+	UserUnsubscribeRequest_Default UserUnsubscribeRequest_Mode = 0 // The same as Freeze
+	UserUnsubscribeRequest_Purge   UserUnsubscribeRequest_Mode = 1 // Remove items of the dao from the feed
+	UserUnsubscribeRequest_Archive UserUnsubscribeRequest_Mode = 2 // Archive items of the dao
+	UserUnsubscribeRequest_Freeze  UserUnsubscribeRequest_Mode = 3 // Keep items of the dao as they are
+)
+
+// Enum value maps for UserUnsubscribeRequest_Mode.
+var (
+	UserUnsubscribeRequest_Mode_name = map[int32]string{
+		0: "Default",
+		1: "Purge",
+		2: "Archive",
+		3: "Freeze",
+	}
+	UserUnsubscribeRequest_Mode_value = map[string]int32{
+		"Default": 0,
+		"Purge":   1,
+		"Archive": 2,
+		"Freeze":  3,
+	}
+)
+
+func (x UserUnsubscribeRequest_Mode) Enum() *UserUnsubscribeRequest_Mode {
+	p := new(UserUnsubscribeRequest_Mode)
+	*p = x
+	return p
+}
+
+func (x UserUnsubscribeRequest_Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserUnsubscribeRequest_Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_feedapi_feed_proto_enumTypes[1].Descriptor()
+}
+
+func (UserUnsubscribeRequest_Mode) Type() protoreflect.EnumType {
+	return &file_feedapi_feed_proto_enumTypes[1]
+}
+
+func (x UserUnsubscribeRequest_Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserUnsubscribeRequest_Mode.Descriptor instead.
+func (UserUnsubscribeRequest_Mode) EnumDescriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type AutoArchiveRule_Kind int32
+
+const (
+	AutoArchiveRule_AfterEnd     AutoArchiveRule_Kind = 0 // Archive proposals when days have passed since the end of voting
+	AutoArchiveRule_ReadAndEnded AutoArchiveRule_Kind = 1 // Archive read proposals when the voting has ended
+	AutoArchiveRule_States       AutoArchiveRule_Kind = 2 // Archive proposals in one of states at once, e.g. failed or defeated
+	AutoArchiveRule_NeverDao     AutoArchiveRule_Kind = 3 // Never archive proposals of dao_ids
+)
+
+// Enum value maps for AutoArchiveRule_Kind.
+var (
+	AutoArchiveRule_Kind_name = map[int32]string{
+		0: "AfterEnd",
+		1: "ReadAndEnded",
+		2: "States",
+		3: "NeverDao",
+	}
+	AutoArchiveRule_Kind_value = map[string]int32{
+		"AfterEnd":     0,
+		"ReadAndEnded": 1,
+		"States":       2,
+		"NeverDao":     3,
+	}
+)
+
+func (x AutoArchiveRule_Kind) Enum() *AutoArchiveRule_Kind {
+	p := new(AutoArchiveRule_Kind)
+	*p = x
+	return p
+}
+
+func (x AutoArchiveRule_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AutoArchiveRule_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_feedapi_feed_proto_enumTypes[2].Descriptor()
+}
+
+func (AutoArchiveRule_Kind) Type() protoreflect.EnumType {
+	return &file_feedapi_feed_proto_enumTypes[2]
+}
+
+func (x AutoArchiveRule_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AutoArchiveRule_Kind.Descriptor instead.
+func (AutoArchiveRule_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{20, 0}
+}
+
+type BackfillStatus_Status int32
+
+const (
+	BackfillStatus_Pending   BackfillStatus_Status = 0 // Waiting for the first run or the retry
+	BackfillStatus_Running   BackfillStatus_Status = 1
+	BackfillStatus_Completed BackfillStatus_Status = 2
+	BackfillStatus_Failed    BackfillStatus_Status = 3 // Ran out of attempts, the next subscribe starts it again
+	BackfillStatus_Canceled  BackfillStatus_Status = 4 // The subscriber left the dao, the next subscribe starts it again
+)
+
+// Enum value maps for BackfillStatus_Status.
+var (
+	BackfillStatus_Status_name = map[int32]string{
+		0: "Pending",
+		1: "Running",
+		2: "Completed",
+		3: "Failed",
+		4: "Canceled",
+	}
+	BackfillStatus_Status_value = map[string]int32{
+		"Pending":   0,
+		"Running":   1,
+		"Completed": 2,
+		"Failed":    3,
+		"Canceled":  4,
+	}
+)
+
+func (x BackfillStatus_Status) Enum() *BackfillStatus_Status {
+	p := new(BackfillStatus_Status)
+	*p = x
+	return p
+}
+
+func (x BackfillStatus_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BackfillStatus_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_feedapi_feed_proto_enumTypes[3].Descriptor()
+}
+
+func (BackfillStatus_Status) Type() protoreflect.EnumType {
+	return &file_feedapi_feed_proto_enumTypes[3]
+}
+
+func (x BackfillStatus_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BackfillStatus_Status.Descriptor instead.
+func (BackfillStatus_Status) EnumDescriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{21, 0}
+}
+
+type GetUserFeedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId  string                            `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	ReadState     inboxapi.GetUserFeedRequest_State `protobuf:"varint,2,opt,name=read_state,json=readState,proto3,enum=inboxapi.GetUserFeedRequest_State" json:"read_state,omitempty"`
+	ArchivedState inboxapi.GetUserFeedRequest_State `protobuf:"varint,3,opt,name=archived_state,json=archivedState,proto3,enum=inboxapi.GetUserFeedRequest_State" json:"archived_state,omitempty"`
+	Limit         uint32                            `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Value of FeedPage.next_cursor from the previous page, empty for the first page
+	Cursor string `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Filters below are applied to the list and counters, empty values mean no filtering
+	DaoIds []string `protobuf:"bytes,6,rep,name=dao_ids,json=daoIds,proto3" json:"dao_ids,omitempty"`
+	// Feed item actions, e.g. proposal.voting.ends_soon
+	Actions []string `protobuf:"bytes,7,rep,name=actions,proto3" json:"actions,omitempty"`
+	// Feed item type: proposal, discussion or dao
+	Type string `protobuf:"bytes,8,opt,name=type,proto3" json:"type,omitempty"`
+	// Proposal states from the snapshot, e.g. active
+	ProposalStates []string `protobuf:"bytes,9,rep,name=proposal_states,json=proposalStates,proto3" json:"proposal_states,omitempty"`
+	// Shows or hides discussion items, they are included by default
+	DiscussionState inboxapi.GetUserFeedRequest_State `protobuf:"varint,10,opt,name=discussion_state,json=discussionState,proto3,enum=inboxapi.GetUserFeedRequest_State" json:"discussion_state,omitempty"`
+	// Snoozed items are hidden by default, they are not counted in total and unread counts either
+	Snoozed GetUserFeedRequest_Snoozed `protobuf:"varint,11,opt,name=snoozed,proto3,enum=feedapi.GetUserFeedRequest_Snoozed" json:"snoozed,omitempty"`
+	// Archive reasons: manual, voted, expired, bulk-by-time or unsubscribed
+	ArchiveReasons []string `protobuf:"bytes,12,rep,name=archive_reasons,json=archiveReasons,proto3" json:"archive_reasons,omitempty"`
+}
+
+func (x *GetUserFeedRequest) Reset() {
+	*x = GetUserFeedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserFeedRequest) ProtoMessage() {}
+
+func (x *GetUserFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserFeedRequest.ProtoReflect.Descriptor instead.
+func (*GetUserFeedRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUserFeedRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *GetUserFeedRequest) GetReadState() inboxapi.GetUserFeedRequest_State {
+	if x != nil {
+		return x.ReadState
+	}
+	return inboxapi.GetUserFeedRequest_State(0)
+}
+
+func (x *GetUserFeedRequest) GetArchivedState() inboxapi.GetUserFeedRequest_State {
+	if x != nil {
+		return x.ArchivedState
+	}
+	return inboxapi.GetUserFeedRequest_State(0)
+}
+
+func (x *GetUserFeedRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetUserFeedRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetUserFeedRequest) GetDaoIds() []string {
+	if x != nil {
+		return x.DaoIds
+	}
+	return nil
+}
+
+func (x *GetUserFeedRequest) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *GetUserFeedRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GetUserFeedRequest) GetProposalStates() []string {
+	if x != nil {
+		return x.ProposalStates
+	}
+	return nil
+}
+
+func (x *GetUserFeedRequest) GetDiscussionState() inboxapi.GetUserFeedRequest_State {
+	if x != nil {
+		return x.DiscussionState
+	}
+	return inboxapi.GetUserFeedRequest_State(0)
+}
+
+func (x *GetUserFeedRequest) GetSnoozed() GetUserFeedRequest_Snoozed {
+	if x != nil {
+		return x.Snoozed
+	}
+	return GetUserFeedRequest_Hidden
+}
+
+func (x *GetUserFeedRequest) GetArchiveReasons() []string {
+	if x != nil {
+		return x.ArchiveReasons
+	}
+	return nil
+}
+
+type FeedPage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List        []*inboxapi.FeedItem `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	TotalCount  uint32               `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UnreadCount uint32               `protobuf:"varint,3,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	// Empty when there are no more items
+	NextCursor string `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// Why items were marked as unread by significant updates, e.g. proposal.voting.quorum_reached or state:succeeded.
+	// Keyed by item id, items which were never resurfaced are omitted
+	ResurfacedReasons map[string]string `protobuf:"bytes,5,rep,name=resurfaced_reasons,json=resurfacedReasons,proto3" json:"resurfaced_reasons,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Which path archived items, e.g. manual or expired. Keyed by item id, not archived items are omitted
+	ArchiveReasons map[string]string `protobuf:"bytes,6,rep,name=archive_reasons,json=archiveReasons,proto3" json:"archive_reasons,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *FeedPage) Reset() {
+	*x = FeedPage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeedPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedPage) ProtoMessage() {}
+
+func (x *FeedPage) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedPage.ProtoReflect.Descriptor instead.
+func (*FeedPage) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FeedPage) GetList() []*inboxapi.FeedItem {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *FeedPage) GetTotalCount() uint32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *FeedPage) GetUnreadCount() uint32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *FeedPage) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *FeedPage) GetResurfacedReasons() map[string]string {
+	if x != nil {
+		return x.ResurfacedReasons
+	}
+	return nil
+}
+
+func (x *FeedPage) GetArchiveReasons() map[string]string {
+	if x != nil {
+		return x.ArchiveReasons
+	}
+	return nil
+}
+
+type WatchUserFeedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	// Value of FeedUpdate.resume_token from the last received update to continue the interrupted stream
+	ResumeToken string `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+}
+
+func (x *WatchUserFeedRequest) Reset() {
+	*x = WatchUserFeedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchUserFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUserFeedRequest) ProtoMessage() {}
+
+func (x *WatchUserFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUserFeedRequest.ProtoReflect.Descriptor instead.
+func (*WatchUserFeedRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WatchUserFeedRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *WatchUserFeedRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type FeedUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ResumeToken string `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// Created or changed items
+	Items []*inboxapi.FeedItem  `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Stats *inboxapi.UnreadStats `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+	// Change of the counters since the previous update in the stream
+	StatsDelta *StatsDelta `protobuf:"bytes,4,opt,name=stats_delta,json=statsDelta,proto3" json:"stats_delta,omitempty"`
+	// Items were changed in bulk or the stream can't be resumed, the client has to reload the feed
+	Refresh bool `protobuf:"varint,5,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	// The same as FeedPage.resurfaced_reasons for changed items
+	ResurfacedReasons map[string]string `protobuf:"bytes,6,rep,name=resurfaced_reasons,json=resurfacedReasons,proto3" json:"resurfaced_reasons,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The same as FeedPage.archive_reasons for changed items
+	ArchiveReasons map[string]string `protobuf:"bytes,7,rep,name=archive_reasons,json=archiveReasons,proto3" json:"archive_reasons,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *FeedUpdate) Reset() {
+	*x = FeedUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeedUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedUpdate) ProtoMessage() {}
+
+func (x *FeedUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedUpdate.ProtoReflect.Descriptor instead.
+func (*FeedUpdate) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FeedUpdate) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *FeedUpdate) GetItems() []*inboxapi.FeedItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *FeedUpdate) GetStats() *inboxapi.UnreadStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *FeedUpdate) GetStatsDelta() *StatsDelta {
+	if x != nil {
+		return x.StatsDelta
+	}
+	return nil
+}
+
+func (x *FeedUpdate) GetRefresh() bool {
+	if x != nil {
+		return x.Refresh
+	}
+	return false
+}
+
+func (x *FeedUpdate) GetResurfacedReasons() map[string]string {
+	if x != nil {
+		return x.ResurfacedReasons
+	}
+	return nil
+}
+
+func (x *FeedUpdate) GetArchiveReasons() map[string]string {
+	if x != nil {
+		return x.ArchiveReasons
+	}
+	return nil
+}
+
+type StatsDelta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalCount  int32 `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UnreadCount int32 `protobuf:"varint,2,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+}
+
+func (x *StatsDelta) Reset() {
+	*x = StatsDelta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsDelta) ProtoMessage() {}
+
+func (x *StatsDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsDelta.ProtoReflect.Descriptor instead.
+func (*StatsDelta) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StatsDelta) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *StatsDelta) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+type GetDaoCountersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+}
+
+func (x *GetDaoCountersRequest) Reset() {
+	*x = GetDaoCountersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDaoCountersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDaoCountersRequest) ProtoMessage() {}
+
+func (x *GetDaoCountersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDaoCountersRequest.ProtoReflect.Descriptor instead.
+func (*GetDaoCountersRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetDaoCountersRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+type DaoCounters struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DaoId       string `protobuf:"bytes,1,opt,name=dao_id,json=daoId,proto3" json:"dao_id,omitempty"`
+	TotalCount  uint32 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UnreadCount uint32 `protobuf:"varint,3,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+}
+
+func (x *DaoCounters) Reset() {
+	*x = DaoCounters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DaoCounters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DaoCounters) ProtoMessage() {}
+
+func (x *DaoCounters) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DaoCounters.ProtoReflect.Descriptor instead.
+func (*DaoCounters) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DaoCounters) GetDaoId() string {
+	if x != nil {
+		return x.DaoId
+	}
+	return ""
+}
+
+func (x *DaoCounters) GetTotalCount() uint32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *DaoCounters) GetUnreadCount() uint32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+type DaoCountersList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sorted by dao id
+	List []*DaoCounters `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+}
+
+func (x *DaoCountersList) Reset() {
+	*x = DaoCountersList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DaoCountersList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DaoCountersList) ProtoMessage() {}
+
+func (x *DaoCountersList) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DaoCountersList.ProtoReflect.Descriptor instead.
+func (*DaoCountersList) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DaoCountersList) GetList() []*DaoCounters {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type UserUnsubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string                      `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	DaoId        string                      `protobuf:"bytes,2,opt,name=dao_id,json=daoId,proto3" json:"dao_id,omitempty"`
+	Mode         UserUnsubscribeRequest_Mode `protobuf:"varint,3,opt,name=mode,proto3,enum=feedapi.UserUnsubscribeRequest_Mode" json:"mode,omitempty"`
+}
+
+func (x *UserUnsubscribeRequest) Reset() {
+	*x = UserUnsubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserUnsubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUnsubscribeRequest) ProtoMessage() {}
+
+func (x *UserUnsubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUnsubscribeRequest.ProtoReflect.Descriptor instead.
+func (*UserUnsubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UserUnsubscribeRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *UserUnsubscribeRequest) GetDaoId() string {
+	if x != nil {
+		return x.DaoId
+	}
+	return ""
+}
+
+func (x *UserUnsubscribeRequest) GetMode() UserUnsubscribeRequest_Mode {
+	if x != nil {
+		return x.Mode
+	}
+	return UserUnsubscribeRequest_Default
+}
+
+type GetBackfillStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	// Empty means all daos
+	DaoId string `protobuf:"bytes,2,opt,name=dao_id,json=daoId,proto3" json:"dao_id,omitempty"`
+}
+
+func (x *GetBackfillStatusRequest) Reset() {
+	*x = GetBackfillStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBackfillStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBackfillStatusRequest) ProtoMessage() {}
+
+func (x *GetBackfillStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBackfillStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetBackfillStatusRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetBackfillStatusRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *GetBackfillStatusRequest) GetDaoId() string {
+	if x != nil {
+		return x.DaoId
+	}
+	return ""
+}
+
+type UserBulkSubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	// Up to 100 daos, duplicates are ignored
+	DaoIds []string `protobuf:"bytes,2,rep,name=dao_ids,json=daoIds,proto3" json:"dao_ids,omitempty"`
+}
+
+func (x *UserBulkSubscribeRequest) Reset() {
+	*x = UserBulkSubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserBulkSubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserBulkSubscribeRequest) ProtoMessage() {}
+
+func (x *UserBulkSubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserBulkSubscribeRequest.ProtoReflect.Descriptor instead.
+func (*UserBulkSubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UserBulkSubscribeRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *UserBulkSubscribeRequest) GetDaoIds() []string {
+	if x != nil {
+		return x.DaoIds
+	}
+	return nil
+}
+
+type GetFeedSettingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+}
+
+func (x *GetFeedSettingsRequest) Reset() {
+	*x = GetFeedSettingsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFeedSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeedSettingsRequest) ProtoMessage() {}
+
+func (x *GetFeedSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeedSettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetFeedSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetFeedSettingsRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+type UpdateFeedSettingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	// Opt-in to DAO-level items, e.g. the renamed dao or new strategies. Existing DAO-level items are removed on opt-out
+	DaoEvents *bool `protobuf:"varint,2,opt,name=dao_events,json=daoEvents,proto3,oneof" json:"dao_events,omitempty"`
+	// Replaces auto-archive rules when set, the empty list restores the default rule
+	AutoarchiveRules *AutoArchiveRules `protobuf:"bytes,3,opt,name=autoarchive_rules,json=autoarchiveRules,proto3" json:"autoarchive_rules,omitempty"`
+}
+
+func (x *UpdateFeedSettingsRequest) Reset() {
+	*x = UpdateFeedSettingsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFeedSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFeedSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateFeedSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFeedSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFeedSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateFeedSettingsRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *UpdateFeedSettingsRequest) GetDaoEvents() bool {
+	if x != nil && x.DaoEvents != nil {
+		return *x.DaoEvents
+	}
+	return false
+}
+
+func (x *UpdateFeedSettingsRequest) GetAutoarchiveRules() *AutoArchiveRules {
+	if x != nil {
+		return x.AutoarchiveRules
+	}
+	return nil
+}
+
+type SnoozeItemsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string   `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	Ids          []string `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	// At least one of until and until_action has to be set
+	Until *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	// Timeline action which wakes items up, e.g. proposal.voting.ends_soon
+	UntilAction string `protobuf:"bytes,4,opt,name=until_action,json=untilAction,proto3" json:"until_action,omitempty"`
+}
+
+func (x *SnoozeItemsRequest) Reset() {
+	*x = SnoozeItemsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnoozeItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnoozeItemsRequest) ProtoMessage() {}
+
+func (x *SnoozeItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnoozeItemsRequest.ProtoReflect.Descriptor instead.
+func (*SnoozeItemsRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SnoozeItemsRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *SnoozeItemsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *SnoozeItemsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *SnoozeItemsRequest) GetUntilAction() string {
+	if x != nil {
+		return x.UntilAction
+	}
+	return ""
+}
+
+type UnsnoozeItemsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string   `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	Ids          []string `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *UnsnoozeItemsRequest) Reset() {
+	*x = UnsnoozeItemsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnsnoozeItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsnoozeItemsRequest) ProtoMessage() {}
+
+func (x *UnsnoozeItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsnoozeItemsRequest.ProtoReflect.Descriptor instead.
+func (*UnsnoozeItemsRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnsnoozeItemsRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *UnsnoozeItemsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetItemHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SubscriberId string `protobuf:"bytes,1,opt,name=subscriber_id,json=subscriberId,proto3" json:"subscriber_id,omitempty"`
+	Limit        uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Value of ItemHistoryPage.next_cursor from the previous page, empty for the first page
+	Cursor string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Empty means all items
+	ItemId string `protobuf:"bytes,4,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+}
+
+func (x *GetItemHistoryRequest) Reset() {
+	*x = GetItemHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetItemHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetItemHistoryRequest) ProtoMessage() {}
+
+func (x *GetItemHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetItemHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetItemHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetItemHistoryRequest) GetSubscriberId() string {
+	if x != nil {
+		return x.SubscriberId
+	}
+	return ""
+}
+
+func (x *GetItemHistoryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetItemHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetItemHistoryRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+type ItemHistoryPage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List []*ItemHistoryEvent `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	// Empty when there are no more events
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+}
+
+func (x *ItemHistoryPage) Reset() {
+	*x = ItemHistoryPage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ItemHistoryPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemHistoryPage) ProtoMessage() {}
+
+func (x *ItemHistoryPage) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemHistoryPage.ProtoReflect.Descriptor instead.
+func (*ItemHistoryPage) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ItemHistoryPage) GetList() []*ItemHistoryEvent {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *ItemHistoryPage) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ItemHistoryEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemId string `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	// read, unread, archived or unarchived
+	Transition string `protobuf:"bytes,2,opt,name=transition,proto3" json:"transition,omitempty"`
+	// Which path made the change: grpc, vote_consumer, worker, subscription_consumer or feed_update
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// Set for archived transitions only, e.g. manual or expired
+	ArchiveReason string                 `protobuf:"bytes,4,opt,name=archive_reason,json=archiveReason,proto3" json:"archive_reason,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+}
+
+func (x *ItemHistoryEvent) Reset() {
+	*x = ItemHistoryEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ItemHistoryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemHistoryEvent) ProtoMessage() {}
+
+func (x *ItemHistoryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemHistoryEvent.ProtoReflect.Descriptor instead.
+func (*ItemHistoryEvent) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ItemHistoryEvent) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *ItemHistoryEvent) GetTransition() string {
+	if x != nil {
+		return x.Transition
+	}
+	return ""
+}
+
+func (x *ItemHistoryEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ItemHistoryEvent) GetArchiveReason() string {
+	if x != nil {
+		return x.ArchiveReason
+	}
+	return ""
+}
+
+func (x *ItemHistoryEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type FeedSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DaoEvents            bool   `protobuf:"varint,1,opt,name=dao_events,json=daoEvents,proto3" json:"dao_events,omitempty"`
+	AutoarchiveAfterDays uint32 `protobuf:"varint,2,opt,name=autoarchive_after_days,json=autoarchiveAfterDays,proto3" json:"autoarchive_after_days,omitempty"`
+	// Rules applied by the auto-archive worker, the default one archives proposals autoarchive_after_days after the end
+	AutoarchiveRules []*AutoArchiveRule `protobuf:"bytes,3,rep,name=autoarchive_rules,json=autoarchiveRules,proto3" json:"autoarchive_rules,omitempty"`
+}
+
+func (x *FeedSettings) Reset() {
+	*x = FeedSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeedSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedSettings) ProtoMessage() {}
+
+func (x *FeedSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedSettings.ProtoReflect.Descriptor instead.
+func (*FeedSettings) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FeedSettings) GetDaoEvents() bool {
+	if x != nil {
+		return x.DaoEvents
+	}
+	return false
+}
+
+func (x *FeedSettings) GetAutoarchiveAfterDays() uint32 {
+	if x != nil {
+		return x.AutoarchiveAfterDays
+	}
+	return 0
+}
+
+func (x *FeedSettings) GetAutoarchiveRules() []*AutoArchiveRule {
+	if x != nil {
+		return x.AutoarchiveRules
+	}
+	return nil
+}
+
+type AutoArchiveRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Up to 20 rules, the proposal is archived if any of them matches unless it's excluded by NeverDao
+	List []*AutoArchiveRule `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+}
+
+func (x *AutoArchiveRules) Reset() {
+	*x = AutoArchiveRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutoArchiveRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoArchiveRules) ProtoMessage() {}
+
+func (x *AutoArchiveRules) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoArchiveRules.ProtoReflect.Descriptor instead.
+func (*AutoArchiveRules) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AutoArchiveRules) GetList() []*AutoArchiveRule {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type AutoArchiveRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind   AutoArchiveRule_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=feedapi.AutoArchiveRule_Kind" json:"kind,omitempty"`
+	Days   uint32               `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	States []string             `protobuf:"bytes,3,rep,name=states,proto3" json:"states,omitempty"`
+	DaoIds []string             `protobuf:"bytes,4,rep,name=dao_ids,json=daoIds,proto3" json:"dao_ids,omitempty"`
+}
+
+func (x *AutoArchiveRule) Reset() {
+	*x = AutoArchiveRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutoArchiveRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoArchiveRule) ProtoMessage() {}
+
+func (x *AutoArchiveRule) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoArchiveRule.ProtoReflect.Descriptor instead.
+func (*AutoArchiveRule) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AutoArchiveRule) GetKind() AutoArchiveRule_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return AutoArchiveRule_AfterEnd
+}
+
+func (x *AutoArchiveRule) GetDays() uint32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *AutoArchiveRule) GetStates() []string {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *AutoArchiveRule) GetDaoIds() []string {
+	if x != nil {
+		return x.DaoIds
+	}
+	return nil
+}
+
+type BackfillStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DaoId  string                `protobuf:"bytes,1,opt,name=dao_id,json=daoId,proto3" json:"dao_id,omitempty"`
+	Status BackfillStatus_Status `protobuf:"varint,2,opt,name=status,proto3,enum=feedapi.BackfillStatus_Status" json:"status,omitempty"`
+	// Number of stored items and the total number of items in the core feed
+	Processed uint32 `protobuf:"varint,3,opt,name=processed,proto3" json:"processed,omitempty"`
+	Total     uint32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Attempts  uint32 `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// The last error, empty if the last attempt succeeded
+	Error      string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	NextRunAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	FinishedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+}
+
+func (x *BackfillStatus) Reset() {
+	*x = BackfillStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackfillStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillStatus) ProtoMessage() {}
+
+func (x *BackfillStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillStatus.ProtoReflect.Descriptor instead.
+func (*BackfillStatus) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BackfillStatus) GetDaoId() string {
+	if x != nil {
+		return x.DaoId
+	}
+	return ""
+}
+
+func (x *BackfillStatus) GetStatus() BackfillStatus_Status {
+	if x != nil {
+		return x.Status
+	}
+	return BackfillStatus_Pending
+}
+
+func (x *BackfillStatus) GetProcessed() uint32 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *BackfillStatus) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BackfillStatus) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *BackfillStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BackfillStatus) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+func (x *BackfillStatus) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *BackfillStatus) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type BackfillStatusList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sorted by dao id
+	List []*BackfillStatus `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+}
+
+func (x *BackfillStatusList) Reset() {
+	*x = BackfillStatusList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_feedapi_feed_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackfillStatusList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillStatusList) ProtoMessage() {}
+
+func (x *BackfillStatusList) ProtoReflect() protoreflect.Message {
+	mi := &file_feedapi_feed_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillStatusList.ProtoReflect.Descriptor instead.
+func (*BackfillStatusList) Descriptor() ([]byte, []int) {
+	return file_feedapi_feed_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BackfillStatusList) GetList() []*BackfillStatus {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+var File_feedapi_feed_proto protoreflect.FileDescriptor
+
+var file_feedapi_feed_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x65, 0x65, 0x64, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x1a, 0x1b, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
+	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x69, 0x6e, 0x62,
+	0x6f, 0x78, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x65, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xcb, 0x04, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x41, 0x0a, 0x0a,
+	0x72, 0x65, 0x61, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x22, 0x2e, 0x69, 0x6e, 0x62, 0x6f, 0x78, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x09, 0x72, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x49, 0x0a, 0x0e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6e, 0x62, 0x6f, 0x78, 0x61,
+	0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0d, 0x61, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x61, 0x6f, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x61, 0x6f, 0x49, 0x64,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x27, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x10, 0x64, 0x69, 0x73, 0x63,
+	0x75, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6e, 0x62, 0x6f, 0x78, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0f, 0x64, 0x69, 0x73, 0x63, 0x75, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3d, 0x0a, 0x07, 0x73, 0x6e, 0x6f, 0x6f, 0x7a,
+	0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61,
+	0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x64, 0x52, 0x07, 0x73,
+	0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x22,
+	0x2d, 0x0a, 0x07, 0x53, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x64, 0x12, 0x0a, 0x0a, 0x06, 0x48, 0x69,
+	0x64, 0x64, 0x65, 0x6e, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x64, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4f, 0x6e, 0x6c, 0x79, 0x10, 0x02, 0x22, 0xc9,
+	0x03, 0x0a, 0x08, 0x46, 0x65, 0x65, 0x64, 0x50, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x6c,
+	0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x69, 0x6e, 0x62, 0x6f,
+	0x78, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x6c,
+	0x69, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x75, 0x6e, 0x72, 0x65,
+	0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f,
+	0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65,
+	0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x57, 0x0a, 0x12, 0x72, 0x65, 0x73, 0x75,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x46,
+	0x65, 0x65, 0x64, 0x50, 0x61, 0x67, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11,
+	0x72, 0x65, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x73, 0x12, 0x4e, 0x0a, 0x0f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x5f, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x66, 0x65, 0x65,
+	0x64, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x50, 0x61, 0x67, 0x65, 0x2e, 0x41, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x0e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x73, 0x1a, 0x44, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x64, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13, 0x41, 0x72, 0x63, 0x68, 0x69,
+	0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5e, 0x0a, 0x14, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6d,
+	0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x8c, 0x04, 0x0a, 0x0a, 0x46,
+	0x65, 0x65, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x28, 0x0a, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x69, 0x6e,
+	0x62, 0x6f, 0x78, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x62, 0x6f, 0x78, 0x61, 0x70, 0x69,
+	0x2e, 0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x12, 0x34, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x64, 0x65, 0x6c,
+	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61,
+	0x70, 0x69, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x52, 0x0a, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x66, 0x72,
+	0x65, 0x73, 0x68, 0x12, 0x59, 0x0a, 0x12, 0x72, 0x65, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x64, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2a, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x64, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x72, 0x65, 0x73,
+	0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x12, 0x50,
+	0x0a, 0x0f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70,
+	0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x41, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0e, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73,
+	0x1a, 0x44, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x75, 0x72, 0x66, 0x61, 0x63, 0x65, 0x64, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x13, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x50, 0x0a, 0x0a, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x72, 0x65,
+	0x61, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x75, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x44, 0x61, 0x6f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x22, 0x68, 0x0a, 0x0b, 0x44, 0x61, 0x6f,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x61, 0x6f, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x61, 0x6f, 0x49, 0x64, 0x12,
+	0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x3b, 0x0a, 0x0f, 0x44, 0x61, 0x6f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65,
+	0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x44,
+	0x61, 0x6f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74,
+	0x22, 0xc7, 0x01, 0x0a, 0x16, 0x55, 0x73, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x15, 0x0a, 0x06, 0x64, 0x61, 0x6f, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x64, 0x61, 0x6f, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x22, 0x37, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x75, 0x72, 0x67, 0x65, 0x10,
+	0x01, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x10, 0x02, 0x12, 0x0a,
+	0x0a, 0x06, 0x46, 0x72, 0x65, 0x65, 0x7a, 0x65, 0x10, 0x03, 0x22, 0x56, 0x0a, 0x18, 0x47, 0x65,
+	0x74, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x64,
+	0x61, 0x6f, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x64, 0x61, 0x6f,
+	0x49, 0x64, 0x22, 0x58, 0x0a, 0x18, 0x55, 0x73, 0x65, 0x72, 0x42, 0x75, 0x6c, 0x6b, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23,
+	0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x61, 0x6f, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x61, 0x6f, 0x49, 0x64, 0x73, 0x22, 0x3d, 0x0a, 0x16,
+	0x47, 0x65, 0x74, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x22, 0xbb, 0x01, 0x0a, 0x19,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22,
+	0x0a, 0x0a, 0x64, 0x61, 0x6f, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x64, 0x61, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x46, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x6f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x41, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x10, 0x61, 0x75, 0x74, 0x6f, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64,
+	0x61, 0x6f, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x12, 0x53, 0x6e,
+	0x6f, 0x6f, 0x7a, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x6e, 0x74,
+	0x69, 0x6c, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x14,
+	0x55, 0x6e, 0x73, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0x83, 0x01, 0x0a, 0x15,
+	0x47, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d,
+	0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49,
+	0x64, 0x22, 0x61, 0x0a, 0x0f, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x50, 0x61, 0x67, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x49, 0x74, 0x65,
+	0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x6c,
+	0x69, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73,
+	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75,
+	0x72, 0x73, 0x6f, 0x72, 0x22, 0xc7, 0x01, 0x0a, 0x10, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65,
+	0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d,
+	0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x3b, 0x0a, 0x0b, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0a, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x41, 0x74, 0x22, 0xaa,
+	0x01, 0x0a, 0x0c, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x6f, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x64, 0x61, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x34,
+	0x0a, 0x16, 0x61, 0x75, 0x74, 0x6f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x5f, 0x61, 0x66,
+	0x74, 0x65, 0x72, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14,
+	0x61, 0x75, 0x74, 0x6f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72,
+	0x44, 0x61, 0x79, 0x73, 0x12, 0x45, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x6f, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x41, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x10, 0x61, 0x75, 0x74, 0x6f, 0x61,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x40, 0x0a, 0x10, 0x41,
+	0x75, 0x74, 0x6f, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12,
+	0x2c, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x41, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x22, 0xcb, 0x01,
+	0x0a, 0x0f, 0x41, 0x75, 0x74, 0x6f, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x31, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1d, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x41, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73,
+	0x12, 0x17, 0x0a, 0x07, 0x64, 0x61, 0x6f, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x64, 0x61, 0x6f, 0x49, 0x64, 0x73, 0x22, 0x40, 0x0a, 0x04, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x66, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x64, 0x10, 0x00, 0x12,
+	0x10, 0x0a, 0x0c, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6e, 0x64, 0x45, 0x6e, 0x64, 0x65, 0x64, 0x10,
+	0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x10, 0x02, 0x12, 0x0c, 0x0a,
+	0x08, 0x4e, 0x65, 0x76, 0x65, 0x72, 0x44, 0x61, 0x6f, 0x10, 0x03, 0x22, 0xc6, 0x03, 0x0a, 0x0e,
+	0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x15,
+	0x0a, 0x06, 0x64, 0x61, 0x6f, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x64, 0x61, 0x6f, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e,
+	0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x3a, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x72, 0x75, 0x6e, 0x5f,
+	0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x52, 0x75, 0x6e, 0x41, 0x74, 0x12,
+	0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3b, 0x0a, 0x0b, 0x66, 0x69,
+	0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x66, 0x69, 0x6e,
+	0x69, 0x73, 0x68, 0x65, 0x64, 0x41, 0x74, 0x22, 0x4b, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x43,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
+	0x65, 0x64, 0x10, 0x04, 0x22, 0x41, 0x0a, 0x12, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x6c, 0x69,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61,
+	0x70, 0x69, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x32, 0xc6, 0x06, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72,
+	0x46, 0x65, 0x65, 0x64, 0x12, 0x3d, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x46,
+	0x65, 0x65, 0x64, 0x12, 0x1b, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x50,
+	0x61, 0x67, 0x65, 0x12, 0x45, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x55, 0x73, 0x65, 0x72,
+	0x46, 0x65, 0x65, 0x64, 0x12, 0x1d, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x55, 0x73, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65,
+	0x65, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x44, 0x61, 0x6f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x2e, 0x66,
+	0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x6f, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x66,
+	0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x61, 0x6f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65,
+	0x72, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x4a, 0x0a, 0x0f, 0x55, 0x73, 0x65, 0x72, 0x55, 0x6e,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1f, 0x2e, 0x66, 0x65, 0x65, 0x64,
+	0x61, 0x70, 0x69, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x53, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c,
+	0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70,
+	0x69, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x66, 0x65, 0x65,
+	0x64, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69, 0x6c, 0x6c, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x53, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72, 0x42,
+	0x75, 0x6c, 0x6b, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x21, 0x2e, 0x66,
+	0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x42, 0x75, 0x6c, 0x6b, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x66, 0x69,
+	0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x49, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x1f, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x65, 0x65,
+	0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x15, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x53,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x4f, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x22, 0x2e,
+	0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x65,
+	0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x15, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x65, 0x64,
+	0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x42, 0x0a, 0x0b, 0x53, 0x6e, 0x6f, 0x6f,
+	0x7a, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1b, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x46, 0x0a, 0x0d,
+	0x55, 0x6e, 0x73, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1d, 0x2e,
+	0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x6e, 0x73, 0x6e, 0x6f, 0x6f, 0x7a, 0x65,
+	0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x4a, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1e, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69,
+	0x2e, 0x47, 0x65, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69,
+	0x2e, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x61, 0x67, 0x65,
+	0x42, 0x0b, 0x5a, 0x09, 0x2e, 0x3b, 0x66, 0x65, 0x65, 0x64, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_feedapi_feed_proto_rawDescOnce sync.Once
+	file_feedapi_feed_proto_rawDescData = file_feedapi_feed_proto_rawDesc
+)
+
+func file_feedapi_feed_proto_rawDescGZIP() []byte {
+	file_feedapi_feed_proto_rawDescOnce.Do(func() {
+		file_feedapi_feed_proto_rawDescData = protoimpl.X.CompressGZIP(file_feedapi_feed_proto_rawDescData)
+	})
+	return file_feedapi_feed_proto_rawDescData
+}
+
+var file_feedapi_feed_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_feedapi_feed_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_feedapi_feed_proto_goTypes = []any{
+	(GetUserFeedRequest_Snoozed)(0),        // 0: feedapi.GetUserFeedRequest.Snoozed
+	(UserUnsubscribeRequest_Mode)(0),       // 1: feedapi.UserUnsubscribeRequest.Mode
+	(AutoArchiveRule_Kind)(0),              // 2: feedapi.AutoArchiveRule.Kind
+	(BackfillStatus_Status)(0),             // 3: feedapi.BackfillStatus.Status
+	(*GetUserFeedRequest)(nil),             // 4: feedapi.GetUserFeedRequest
+	(*FeedPage)(nil),                       // 5: feedapi.FeedPage
+	(*WatchUserFeedRequest)(nil),           // 6: feedapi.WatchUserFeedRequest
+	(*FeedUpdate)(nil),                     // 7: feedapi.FeedUpdate
+	(*StatsDelta)(nil),                     // 8: feedapi.StatsDelta
+	(*GetDaoCountersRequest)(nil),          // 9: feedapi.GetDaoCountersRequest
+	(*DaoCounters)(nil),                    // 10: feedapi.DaoCounters
+	(*DaoCountersList)(nil),                // 11: feedapi.DaoCountersList
+	(*UserUnsubscribeRequest)(nil),         // 12: feedapi.UserUnsubscribeRequest
+	(*GetBackfillStatusRequest)(nil),       // 13: feedapi.GetBackfillStatusRequest
+	(*UserBulkSubscribeRequest)(nil),       // 14: feedapi.UserBulkSubscribeRequest
+	(*GetFeedSettingsRequest)(nil),         // 15: feedapi.GetFeedSettingsRequest
+	(*UpdateFeedSettingsRequest)(nil),      // 16: feedapi.UpdateFeedSettingsRequest
+	(*SnoozeItemsRequest)(nil),             // 17: feedapi.SnoozeItemsRequest
+	(*UnsnoozeItemsRequest)(nil),           // 18: feedapi.UnsnoozeItemsRequest
+	(*GetItemHistoryRequest)(nil),          // 19: feedapi.GetItemHistoryRequest
+	(*ItemHistoryPage)(nil),                // 20: feedapi.ItemHistoryPage
+	(*ItemHistoryEvent)(nil),               // 21: feedapi.ItemHistoryEvent
+	(*FeedSettings)(nil),                   // 22: feedapi.FeedSettings
+	(*AutoArchiveRules)(nil),               // 23: feedapi.AutoArchiveRules
+	(*AutoArchiveRule)(nil),                // 24: feedapi.AutoArchiveRule
+	(*BackfillStatus)(nil),                 // 25: feedapi.BackfillStatus
+	(*BackfillStatusList)(nil),             // 26: feedapi.BackfillStatusList
+	nil,                                    // 27: feedapi.FeedPage.ResurfacedReasonsEntry
+	nil,                                    // 28: feedapi.FeedPage.ArchiveReasonsEntry
+	nil,                                    // 29: feedapi.FeedUpdate.ResurfacedReasonsEntry
+	nil,                                    // 30: feedapi.FeedUpdate.ArchiveReasonsEntry
+	(inboxapi.GetUserFeedRequest_State)(0), // 31: inboxapi.GetUserFeedRequest.State
+	(*inboxapi.FeedItem)(nil),              // 32: inboxapi.FeedItem
+	(*inboxapi.UnreadStats)(nil),           // 33: inboxapi.UnreadStats
+	(*timestamppb.Timestamp)(nil),          // 34: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                  // 35: google.protobuf.Empty
+}
+var file_feedapi_feed_proto_depIdxs = []int32{
+	31, // 0: feedapi.GetUserFeedRequest.read_state:type_name -> inboxapi.GetUserFeedRequest.State
+	31, // 1: feedapi.GetUserFeedRequest.archived_state:type_name -> inboxapi.GetUserFeedRequest.State
+	31, // 2: feedapi.GetUserFeedRequest.discussion_state:type_name -> inboxapi.GetUserFeedRequest.State
+	0,  // 3: feedapi.GetUserFeedRequest.snoozed:type_name -> feedapi.GetUserFeedRequest.Snoozed
+	32, // 4: feedapi.FeedPage.list:type_name -> inboxapi.FeedItem
+	27, // 5: feedapi.FeedPage.resurfaced_reasons:type_name -> feedapi.FeedPage.ResurfacedReasonsEntry
+	28, // 6: feedapi.FeedPage.archive_reasons:type_name -> feedapi.FeedPage.ArchiveReasonsEntry
+	32, // 7: feedapi.FeedUpdate.items:type_name -> inboxapi.FeedItem
+	33, // 8: feedapi.FeedUpdate.stats:type_name -> inboxapi.UnreadStats
+	8,  // 9: feedapi.FeedUpdate.stats_delta:type_name -> feedapi.StatsDelta
+	29, // 10: feedapi.FeedUpdate.resurfaced_reasons:type_name -> feedapi.FeedUpdate.ResurfacedReasonsEntry
+	30, // 11: feedapi.FeedUpdate.archive_reasons:type_name -> feedapi.FeedUpdate.ArchiveReasonsEntry
+	10, // 12: feedapi.DaoCountersList.list:type_name -> feedapi.DaoCounters
+	1,  // 13: feedapi.UserUnsubscribeRequest.mode:type_name -> feedapi.UserUnsubscribeRequest.Mode
+	23, // 14: feedapi.UpdateFeedSettingsRequest.autoarchive_rules:type_name -> feedapi.AutoArchiveRules
+	34, // 15: feedapi.SnoozeItemsRequest.until:type_name -> google.protobuf.Timestamp
+	21, // 16: feedapi.ItemHistoryPage.list:type_name -> feedapi.ItemHistoryEvent
+	34, // 17: feedapi.ItemHistoryEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	24, // 18: feedapi.FeedSettings.autoarchive_rules:type_name -> feedapi.AutoArchiveRule
+	24, // 19: feedapi.AutoArchiveRules.list:type_name -> feedapi.AutoArchiveRule
+	2,  // 20: feedapi.AutoArchiveRule.kind:type_name -> feedapi.AutoArchiveRule.Kind
+	3,  // 21: feedapi.BackfillStatus.status:type_name -> feedapi.BackfillStatus.Status
+	34, // 22: feedapi.BackfillStatus.next_run_at:type_name -> google.protobuf.Timestamp
+	34, // 23: feedapi.BackfillStatus.updated_at:type_name -> google.protobuf.Timestamp
+	34, // 24: feedapi.BackfillStatus.finished_at:type_name -> google.protobuf.Timestamp
+	25, // 25: feedapi.BackfillStatusList.list:type_name -> feedapi.BackfillStatus
+	4,  // 26: feedapi.UserFeed.GetUserFeed:input_type -> feedapi.GetUserFeedRequest
+	6,  // 27: feedapi.UserFeed.WatchUserFeed:input_type -> feedapi.WatchUserFeedRequest
+	9,  // 28: feedapi.UserFeed.GetDaoCounters:input_type -> feedapi.GetDaoCountersRequest
+	12, // 29: feedapi.UserFeed.UserUnsubscribe:input_type -> feedapi.UserUnsubscribeRequest
+	13, // 30: feedapi.UserFeed.GetBackfillStatus:input_type -> feedapi.GetBackfillStatusRequest
+	14, // 31: feedapi.UserFeed.UserBulkSubscribe:input_type -> feedapi.UserBulkSubscribeRequest
+	15, // 32: feedapi.UserFeed.GetFeedSettings:input_type -> feedapi.GetFeedSettingsRequest
+	16, // 33: feedapi.UserFeed.UpdateFeedSettings:input_type -> feedapi.UpdateFeedSettingsRequest
+	17, // 34: feedapi.UserFeed.SnoozeItems:input_type -> feedapi.SnoozeItemsRequest
+	18, // 35: feedapi.UserFeed.UnsnoozeItems:input_type -> feedapi.UnsnoozeItemsRequest
+	19, // 36: feedapi.UserFeed.GetItemHistory:input_type -> feedapi.GetItemHistoryRequest
+	5,  // 37: feedapi.UserFeed.GetUserFeed:output_type -> feedapi.FeedPage
+	7,  // 38: feedapi.UserFeed.WatchUserFeed:output_type -> feedapi.FeedUpdate
+	11, // 39: feedapi.UserFeed.GetDaoCounters:output_type -> feedapi.DaoCountersList
+	35, // 40: feedapi.UserFeed.UserUnsubscribe:output_type -> google.protobuf.Empty
+	26, // 41: feedapi.UserFeed.GetBackfillStatus:output_type -> feedapi.BackfillStatusList
+	26, // 42: feedapi.UserFeed.UserBulkSubscribe:output_type -> feedapi.BackfillStatusList
+	22, // 43: feedapi.UserFeed.GetFeedSettings:output_type -> feedapi.FeedSettings
+	22, // 44: feedapi.UserFeed.UpdateFeedSettings:output_type -> feedapi.FeedSettings
+	35, // 45: feedapi.UserFeed.SnoozeItems:output_type -> google.protobuf.Empty
+	35, // 46: feedapi.UserFeed.UnsnoozeItems:output_type -> google.protobuf.Empty
+	20, // 47: feedapi.UserFeed.GetItemHistory:output_type -> feedapi.ItemHistoryPage
+	37, // [37:48] is the sub-list for method output_type
+	26, // [26:37] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_feedapi_feed_proto_init() }
+func file_feedapi_feed_proto_init() {
+	if File_feedapi_feed_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_feedapi_feed_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserFeedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*FeedPage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchUserFeedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FeedUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*StatsDelta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDaoCountersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*DaoCounters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*DaoCountersList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*UserUnsubscribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*GetBackfillStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*UserBulkSubscribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*GetFeedSettingsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateFeedSettingsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*SnoozeItemsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*UnsnoozeItemsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*GetItemHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*ItemHistoryPage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*ItemHistoryEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*FeedSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*AutoArchiveRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*AutoArchiveRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*BackfillStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_feedapi_feed_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*BackfillStatusList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_feedapi_feed_proto_msgTypes[12].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_feedapi_feed_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_feedapi_feed_proto_goTypes,
+		DependencyIndexes: file_feedapi_feed_proto_depIdxs,
+		EnumInfos:         file_feedapi_feed_proto_enumTypes,
+		MessageInfos:      file_feedapi_feed_proto_msgTypes,
+	}.Build()
+	File_feedapi_feed_proto = out.File
+	file_feedapi_feed_proto_rawDesc = nil
+	file_feedapi_feed_proto_goTypes = nil
+	file_feedapi_feed_proto_depIdxs = nil
+}
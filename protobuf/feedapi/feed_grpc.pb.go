@@ -0,0 +1,542 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.3
+// source: feedapi/feed.proto
+
+package feedapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserFeed_GetUserFeed_FullMethodName        = "/feedapi.UserFeed/GetUserFeed"
+	UserFeed_WatchUserFeed_FullMethodName      = "/feedapi.UserFeed/WatchUserFeed"
+	UserFeed_GetDaoCounters_FullMethodName     = "/feedapi.UserFeed/GetDaoCounters"
+	UserFeed_UserUnsubscribe_FullMethodName    = "/feedapi.UserFeed/UserUnsubscribe"
+	UserFeed_GetBackfillStatus_FullMethodName  = "/feedapi.UserFeed/GetBackfillStatus"
+	UserFeed_UserBulkSubscribe_FullMethodName  = "/feedapi.UserFeed/UserBulkSubscribe"
+	UserFeed_GetFeedSettings_FullMethodName    = "/feedapi.UserFeed/GetFeedSettings"
+	UserFeed_UpdateFeedSettings_FullMethodName = "/feedapi.UserFeed/UpdateFeedSettings"
+	UserFeed_SnoozeItems_FullMethodName        = "/feedapi.UserFeed/SnoozeItems"
+	UserFeed_UnsnoozeItems_FullMethodName      = "/feedapi.UserFeed/UnsnoozeItems"
+	UserFeed_GetItemHistory_FullMethodName     = "/feedapi.UserFeed/GetItemHistory"
+)
+
+// UserFeedClient is the client API for UserFeed service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserFeed extends inboxapi.Feed with features that are not a part of the shared inbox protocol yet.
+type UserFeedClient interface {
+	// GetUserFeed returns the page of the subscriber feed addressed by an opaque cursor.
+	GetUserFeed(ctx context.Context, in *GetUserFeedRequest, opts ...grpc.CallOption) (*FeedPage, error)
+	// WatchUserFeed streams changes of the subscriber feed.
+	// The first update contains current counters and refresh flag if the stream can't be resumed.
+	// Changes made by any replica are delivered, the resume token is valid on every replica.
+	WatchUserFeed(ctx context.Context, in *WatchUserFeedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FeedUpdate], error)
+	// GetDaoCounters returns feed counters grouped by dao, daos without actual items are omitted.
+	GetDaoCounters(ctx context.Context, in *GetDaoCountersRequest, opts ...grpc.CallOption) (*DaoCountersList, error)
+	// UserUnsubscribe stops updates of dao items in the subscriber feed, the mode defines what happens to existing items.
+	// Items are updated again after inboxapi.Feed.UserSubscribe.
+	UserUnsubscribe(ctx context.Context, in *UserUnsubscribeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetBackfillStatus returns backfills of the subscriber feed started by inboxapi.Feed.UserSubscribe.
+	GetBackfillStatus(ctx context.Context, in *GetBackfillStatusRequest, opts ...grpc.CallOption) (*BackfillStatusList, error)
+	// UserBulkSubscribe fills the subscriber feed with active proposals of many daos at once, e.g. on onboarding.
+	// Returns the backfill of every dao, failed backfills are retried in background like inboxapi.Feed.UserSubscribe.
+	UserBulkSubscribe(ctx context.Context, in *UserBulkSubscribeRequest, opts ...grpc.CallOption) (*BackfillStatusList, error)
+	// GetFeedSettings returns settings of the subscriber feed.
+	GetFeedSettings(ctx context.Context, in *GetFeedSettingsRequest, opts ...grpc.CallOption) (*FeedSettings, error)
+	// UpdateFeedSettings changes settings which are set in the request and returns the actual ones.
+	UpdateFeedSettings(ctx context.Context, in *UpdateFeedSettingsRequest, opts ...grpc.CallOption) (*FeedSettings, error)
+	// SnoozeItems hides items until the time or the milestone action, whichever comes first. Woken items become unread.
+	// Archived items are skipped, snoozed items are snoozed again with the new conditions.
+	SnoozeItems(ctx context.Context, in *SnoozeItemsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// UnsnoozeItems shows snoozed items again without changing their read state.
+	UnsnoozeItems(ctx context.Context, in *UnsnoozeItemsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetItemHistory returns read and archive transitions of subscriber items from the newest to the oldest.
+	GetItemHistory(ctx context.Context, in *GetItemHistoryRequest, opts ...grpc.CallOption) (*ItemHistoryPage, error)
+}
+
+type userFeedClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserFeedClient(cc grpc.ClientConnInterface) UserFeedClient {
+	return &userFeedClient{cc}
+}
+
+func (c *userFeedClient) GetUserFeed(ctx context.Context, in *GetUserFeedRequest, opts ...grpc.CallOption) (*FeedPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeedPage)
+	err := c.cc.Invoke(ctx, UserFeed_GetUserFeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) WatchUserFeed(ctx context.Context, in *WatchUserFeedRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FeedUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserFeed_ServiceDesc.Streams[0], UserFeed_WatchUserFeed_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUserFeedRequest, FeedUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserFeed_WatchUserFeedClient = grpc.ServerStreamingClient[FeedUpdate]
+
+func (c *userFeedClient) GetDaoCounters(ctx context.Context, in *GetDaoCountersRequest, opts ...grpc.CallOption) (*DaoCountersList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DaoCountersList)
+	err := c.cc.Invoke(ctx, UserFeed_GetDaoCounters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) UserUnsubscribe(ctx context.Context, in *UserUnsubscribeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserFeed_UserUnsubscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) GetBackfillStatus(ctx context.Context, in *GetBackfillStatusRequest, opts ...grpc.CallOption) (*BackfillStatusList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillStatusList)
+	err := c.cc.Invoke(ctx, UserFeed_GetBackfillStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) UserBulkSubscribe(ctx context.Context, in *UserBulkSubscribeRequest, opts ...grpc.CallOption) (*BackfillStatusList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillStatusList)
+	err := c.cc.Invoke(ctx, UserFeed_UserBulkSubscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) GetFeedSettings(ctx context.Context, in *GetFeedSettingsRequest, opts ...grpc.CallOption) (*FeedSettings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeedSettings)
+	err := c.cc.Invoke(ctx, UserFeed_GetFeedSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) UpdateFeedSettings(ctx context.Context, in *UpdateFeedSettingsRequest, opts ...grpc.CallOption) (*FeedSettings, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeedSettings)
+	err := c.cc.Invoke(ctx, UserFeed_UpdateFeedSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) SnoozeItems(ctx context.Context, in *SnoozeItemsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserFeed_SnoozeItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) UnsnoozeItems(ctx context.Context, in *UnsnoozeItemsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserFeed_UnsnoozeItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userFeedClient) GetItemHistory(ctx context.Context, in *GetItemHistoryRequest, opts ...grpc.CallOption) (*ItemHistoryPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ItemHistoryPage)
+	err := c.cc.Invoke(ctx, UserFeed_GetItemHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserFeedServer is the server API for UserFeed service.
+// All implementations must embed UnimplementedUserFeedServer
+// for forward compatibility.
+//
+// UserFeed extends inboxapi.Feed with features that are not a part of the shared inbox protocol yet.
+type UserFeedServer interface {
+	// GetUserFeed returns the page of the subscriber feed addressed by an opaque cursor.
+	GetUserFeed(context.Context, *GetUserFeedRequest) (*FeedPage, error)
+	// WatchUserFeed streams changes of the subscriber feed.
+	// The first update contains current counters and refresh flag if the stream can't be resumed.
+	// Changes made by any replica are delivered, the resume token is valid on every replica.
+	WatchUserFeed(*WatchUserFeedRequest, grpc.ServerStreamingServer[FeedUpdate]) error
+	// GetDaoCounters returns feed counters grouped by dao, daos without actual items are omitted.
+	GetDaoCounters(context.Context, *GetDaoCountersRequest) (*DaoCountersList, error)
+	// UserUnsubscribe stops updates of dao items in the subscriber feed, the mode defines what happens to existing items.
+	// Items are updated again after inboxapi.Feed.UserSubscribe.
+	UserUnsubscribe(context.Context, *UserUnsubscribeRequest) (*emptypb.Empty, error)
+	// GetBackfillStatus returns backfills of the subscriber feed started by inboxapi.Feed.UserSubscribe.
+	GetBackfillStatus(context.Context, *GetBackfillStatusRequest) (*BackfillStatusList, error)
+	// UserBulkSubscribe fills the subscriber feed with active proposals of many daos at once, e.g. on onboarding.
+	// Returns the backfill of every dao, failed backfills are retried in background like inboxapi.Feed.UserSubscribe.
+	UserBulkSubscribe(context.Context, *UserBulkSubscribeRequest) (*BackfillStatusList, error)
+	// GetFeedSettings returns settings of the subscriber feed.
+	GetFeedSettings(context.Context, *GetFeedSettingsRequest) (*FeedSettings, error)
+	// UpdateFeedSettings changes settings which are set in the request and returns the actual ones.
+	UpdateFeedSettings(context.Context, *UpdateFeedSettingsRequest) (*FeedSettings, error)
+	// SnoozeItems hides items until the time or the milestone action, whichever comes first. Woken items become unread.
+	// Archived items are skipped, snoozed items are snoozed again with the new conditions.
+	SnoozeItems(context.Context, *SnoozeItemsRequest) (*emptypb.Empty, error)
+	// UnsnoozeItems shows snoozed items again without changing their read state.
+	UnsnoozeItems(context.Context, *UnsnoozeItemsRequest) (*emptypb.Empty, error)
+	// GetItemHistory returns read and archive transitions of subscriber items from the newest to the oldest.
+	GetItemHistory(context.Context, *GetItemHistoryRequest) (*ItemHistoryPage, error)
+	mustEmbedUnimplementedUserFeedServer()
+}
+
+// UnimplementedUserFeedServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserFeedServer struct{}
+
+func (UnimplementedUserFeedServer) GetUserFeed(context.Context, *GetUserFeedRequest) (*FeedPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserFeed not implemented")
+}
+func (UnimplementedUserFeedServer) WatchUserFeed(*WatchUserFeedRequest, grpc.ServerStreamingServer[FeedUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUserFeed not implemented")
+}
+func (UnimplementedUserFeedServer) GetDaoCounters(context.Context, *GetDaoCountersRequest) (*DaoCountersList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDaoCounters not implemented")
+}
+func (UnimplementedUserFeedServer) UserUnsubscribe(context.Context, *UserUnsubscribeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserUnsubscribe not implemented")
+}
+func (UnimplementedUserFeedServer) GetBackfillStatus(context.Context, *GetBackfillStatusRequest) (*BackfillStatusList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackfillStatus not implemented")
+}
+func (UnimplementedUserFeedServer) UserBulkSubscribe(context.Context, *UserBulkSubscribeRequest) (*BackfillStatusList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserBulkSubscribe not implemented")
+}
+func (UnimplementedUserFeedServer) GetFeedSettings(context.Context, *GetFeedSettingsRequest) (*FeedSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeedSettings not implemented")
+}
+func (UnimplementedUserFeedServer) UpdateFeedSettings(context.Context, *UpdateFeedSettingsRequest) (*FeedSettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFeedSettings not implemented")
+}
+func (UnimplementedUserFeedServer) SnoozeItems(context.Context, *SnoozeItemsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnoozeItems not implemented")
+}
+func (UnimplementedUserFeedServer) UnsnoozeItems(context.Context, *UnsnoozeItemsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsnoozeItems not implemented")
+}
+func (UnimplementedUserFeedServer) GetItemHistory(context.Context, *GetItemHistoryRequest) (*ItemHistoryPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItemHistory not implemented")
+}
+func (UnimplementedUserFeedServer) mustEmbedUnimplementedUserFeedServer() {}
+func (UnimplementedUserFeedServer) testEmbeddedByValue()                  {}
+
+// UnsafeUserFeedServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserFeedServer will
+// result in compilation errors.
+type UnsafeUserFeedServer interface {
+	mustEmbedUnimplementedUserFeedServer()
+}
+
+func RegisterUserFeedServer(s grpc.ServiceRegistrar, srv UserFeedServer) {
+	// If the following call pancis, it indicates UnimplementedUserFeedServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserFeed_ServiceDesc, srv)
+}
+
+func _UserFeed_GetUserFeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserFeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).GetUserFeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_GetUserFeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).GetUserFeed(ctx, req.(*GetUserFeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_WatchUserFeed_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUserFeedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserFeedServer).WatchUserFeed(m, &grpc.GenericServerStream[WatchUserFeedRequest, FeedUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserFeed_WatchUserFeedServer = grpc.ServerStreamingServer[FeedUpdate]
+
+func _UserFeed_GetDaoCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDaoCountersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).GetDaoCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_GetDaoCounters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).GetDaoCounters(ctx, req.(*GetDaoCountersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_UserUnsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserUnsubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).UserUnsubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_UserUnsubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).UserUnsubscribe(ctx, req.(*UserUnsubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_GetBackfillStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBackfillStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).GetBackfillStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_GetBackfillStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).GetBackfillStatus(ctx, req.(*GetBackfillStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_UserBulkSubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserBulkSubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).UserBulkSubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_UserBulkSubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).UserBulkSubscribe(ctx, req.(*UserBulkSubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_GetFeedSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFeedSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).GetFeedSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_GetFeedSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).GetFeedSettings(ctx, req.(*GetFeedSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_UpdateFeedSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFeedSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).UpdateFeedSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_UpdateFeedSettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).UpdateFeedSettings(ctx, req.(*UpdateFeedSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_SnoozeItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnoozeItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).SnoozeItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_SnoozeItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).SnoozeItems(ctx, req.(*SnoozeItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_UnsnoozeItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsnoozeItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).UnsnoozeItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_UnsnoozeItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).UnsnoozeItems(ctx, req.(*UnsnoozeItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserFeed_GetItemHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserFeedServer).GetItemHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserFeed_GetItemHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserFeedServer).GetItemHistory(ctx, req.(*GetItemHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserFeed_ServiceDesc is the grpc.ServiceDesc for UserFeed service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserFeed_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "feedapi.UserFeed",
+	HandlerType: (*UserFeedServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserFeed",
+			Handler:    _UserFeed_GetUserFeed_Handler,
+		},
+		{
+			MethodName: "GetDaoCounters",
+			Handler:    _UserFeed_GetDaoCounters_Handler,
+		},
+		{
+			MethodName: "UserUnsubscribe",
+			Handler:    _UserFeed_UserUnsubscribe_Handler,
+		},
+		{
+			MethodName: "GetBackfillStatus",
+			Handler:    _UserFeed_GetBackfillStatus_Handler,
+		},
+		{
+			MethodName: "UserBulkSubscribe",
+			Handler:    _UserFeed_UserBulkSubscribe_Handler,
+		},
+		{
+			MethodName: "GetFeedSettings",
+			Handler:    _UserFeed_GetFeedSettings_Handler,
+		},
+		{
+			MethodName: "UpdateFeedSettings",
+			Handler:    _UserFeed_UpdateFeedSettings_Handler,
+		},
+		{
+			MethodName: "SnoozeItems",
+			Handler:    _UserFeed_SnoozeItems_Handler,
+		},
+		{
+			MethodName: "UnsnoozeItems",
+			Handler:    _UserFeed_UnsnoozeItems_Handler,
+		},
+		{
+			MethodName: "GetItemHistory",
+			Handler:    _UserFeed_GetItemHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUserFeed",
+			Handler:       _UserFeed_WatchUserFeed_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "feedapi/feed.proto",
+}